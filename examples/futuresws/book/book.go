@@ -19,6 +19,10 @@ func main() {
 	client.URL = os.Getenv("KRAKEN_API_FUTURES_WS_URL")
 	client.REST.BaseURL = os.Getenv("KRAKEN_API_FUTURES_REST_URL")
 	bookManager := derivatives.NewBookManager()
+	bookManager.WebSocket = client
+	bookManager.OnGap.Recurring(func(e *callback.Event[*derivatives.GapEvent]) {
+		fmt.Printf("Gap %s: %s\n", e.Data.Book.Name, helper.ToJSON(e.Data))
+	})
 	bookManager.OnCreateBook.Recurring(func(e *callback.Event[*book.Book]) {
 		b := e.Data
 		fmt.Printf("Create book: %s\n", b.Name)
@@ -37,7 +41,7 @@ func main() {
 			panic(err)
 		}
 	})
-	client.OnConnected.Recurring(func(e *callback.Event[any]) {
+	client.OnConnected.Once(func(e *callback.Event[any]) {
 		if err := client.SubBook(contract); err != nil {
 			panic(err)
 		}
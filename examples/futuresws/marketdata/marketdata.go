@@ -22,7 +22,7 @@ func main() {
 	client.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
 		fmt.Printf("Received: %s\n", e.Data)
 	})
-	client.OnConnected.Recurring(func(e *callback.Event[any]) {
+	client.OnConnected.Once(func(e *callback.Event[any]) {
 		if err := client.SubTicker(contract); err != nil {
 			panic(err)
 		}
@@ -21,7 +21,7 @@ func main() {
 	client.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
 		fmt.Printf("Received: %s\n", e.Data)
 	})
-	client.OnAuthenticated.Recurring(func(e *callback.Event[string]) {
+	client.OnAuthenticated.Once(func(e *callback.Event[string]) {
 		if err := client.SubOpenOrders(); err != nil {
 			panic(err)
 		}
@@ -29,12 +29,13 @@ func main() {
 			panic(err)
 		}
 	})
-	client.OnConnected.Recurring(func(e *callback.Event[any]) {
-		go func() {
-			if err := client.Authenticate(); err != nil {
-				panic(err)
-			}
-		}()
+	client.OnAuthFailed.Once(func(e *callback.Event[error]) {
+		panic(e.Data)
+	})
+	client.OnConnected.Once(func(e *callback.Event[any]) {
+		if err := client.AuthenticateAsync(); err != nil {
+			panic(err)
+		}
 	})
 	if err := client.Connect(); err != nil {
 		panic(err)
@@ -22,7 +22,7 @@ func main() {
 	client.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
 		fmt.Printf("Received: %s\n", e.Data)
 	})
-	client.OnAuthenticated.Recurring(func(e *callback.Event[string]) {
+	client.OnAuthenticated.Once(func(e *callback.Event[string]) {
 		err := client.SubBalances()
 		if err != nil {
 			panic(err)
@@ -32,7 +32,7 @@ func main() {
 			panic(err)
 		}
 	})
-	client.OnConnected.Recurring(func(e *callback.Event[any]) {
+	client.OnConnected.Once(func(e *callback.Event[any]) {
 		if err := client.Authenticate(); err != nil {
 			panic(err)
 		}
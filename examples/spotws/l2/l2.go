@@ -17,6 +17,11 @@ func main() {
 	client.URL = os.Getenv("KRAKEN_API_SPOT_WS_URL")
 	client.REST.BaseURL = os.Getenv("KRAKEN_API_SPOT_REST_URL")
 	bookManager := spot.NewBookManager()
+	bookManager.Resync = true
+	bookManager.WebSocket = client
+	bookManager.OnDesync.Recurring(func(e *callback.Event[*spot.DesyncEvent]) {
+		fmt.Printf("Desync %s: %s\n", e.Data.Book.Name, e.Data.Err)
+	})
 	bookManager.OnCreateBook.Recurring(func(e *callback.Event[*book.Book]) {
 		b := e.Data
 		fmt.Printf("Create book: %s\n", b.Name)
@@ -46,7 +51,7 @@ func main() {
 			panic(err)
 		}
 	})
-	client.OnConnected.Recurring(func(e *callback.Event[any]) {
+	client.OnConnected.Once(func(e *callback.Event[any]) {
 		if err := client.SubBook([]string{"BTC/USD"}, 10); err != nil {
 			panic(err)
 		}
@@ -19,6 +19,11 @@ func main() {
 	client.REST.PublicKey = os.Getenv("KRAKEN_API_SPOT_PUBLIC")
 	client.REST.PrivateKey = os.Getenv("KRAKEN_API_SPOT_SECRET")
 	bookManager := spot.NewBookManager()
+	bookManager.Resync = true
+	bookManager.WebSocket = client
+	bookManager.OnDesync.Recurring(func(e *callback.Event[*spot.DesyncEvent]) {
+		fmt.Printf("Desync %s: %s\n", e.Data.Book.Name, e.Data.Err)
+	})
 	bookManager.OnCreateBook.Recurring(func(e *callback.Event[*book.Book]) {
 		b := e.Data
 		fmt.Printf("Create book: %s\n", b.Name)
@@ -48,12 +53,12 @@ func main() {
 			panic(err)
 		}
 	})
-	client.OnAuthenticated.Recurring(func(e *callback.Event[string]) {
+	client.OnAuthenticated.Once(func(e *callback.Event[string]) {
 		if err := client.SubL3([]string{"BTC/USD"}, 10); err != nil {
 			panic(err)
 		}
 	})
-	client.OnConnected.Recurring(func(e *callback.Event[any]) {
+	client.OnConnected.Once(func(e *callback.Event[any]) {
 		if err := client.Authenticate(); err != nil {
 			panic(err)
 		}
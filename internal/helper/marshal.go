@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
 	"reflect"
 )
 
@@ -80,3 +81,32 @@ func Marshal(opts MarshalOptions) (result MarshalResult, err error) {
 func CreateReadCloser(b []byte) io.ReadCloser {
 	return io.NopCloser(bytes.NewReader(b))
 }
+
+// Unmarshal decodes data of the given media type into map[string]any.
+// Supports "application/json" and "application/x-www-form-urlencoded".
+func Unmarshal(mediaType string, data []byte) (map[string]any, error) {
+	m := make(map[string]any)
+	switch mediaType {
+	case "application/json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&m); err != nil {
+			return nil, fmt.Errorf("json decode: %w", err)
+		}
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("parse query: %w", err)
+		}
+		for k, v := range values {
+			if len(v) == 1 {
+				m[k] = v[0]
+			} else {
+				m[k] = v
+			}
+		}
+	default:
+		return nil, fmt.Errorf("content type \"%s\" not supported", mediaType)
+	}
+	return m, nil
+}
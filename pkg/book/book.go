@@ -2,6 +2,7 @@ package book
 
 import (
 	"math"
+	"sync"
 	"time"
 
 	"github.com/krakenfx/api-go/v2/pkg/callback"
@@ -25,6 +26,9 @@ type Book struct {
 	// Must be disable for whole books.
 	EnableMaxDepth bool `json:"enableMaxDepth,omitempty"`
 
+	// Optional lot size rounding of simulated orders for the instrument named by Name.
+	Normalizer SizeFormatter `json:"-"`
+
 	// Sides
 
 	Bids *Side `json:"bids,omitempty"`
@@ -36,6 +40,9 @@ type Book struct {
 	OnBookCrossed      *callback.Manager[*CrossedResult]          `json:"-"`
 	OnMaxDepthExceeded *callback.Manager[*MaxDepthExceededResult] `json:"-"`
 	OnChecksummed      *callback.Manager[*ChecksumResult]         `json:"-"`
+
+	mux     sync.RWMutex
+	updated time.Time
 }
 
 // New constructs a new [Book] struct with default values.
@@ -44,7 +51,7 @@ func New() *Book {
 	bids.Direction = Bid
 	asks := NewSide()
 	asks.Direction = Ask
-	return &Book{
+	b := &Book{
 		MaxDepth:           1e10,
 		NoBookCrossing:     true,
 		EnableMaxDepth:     true,
@@ -55,36 +62,56 @@ func New() *Book {
 		OnMaxDepthExceeded: callback.NewManager[*MaxDepthExceededResult](),
 		OnChecksummed:      callback.NewManager[*ChecksumResult](),
 	}
+	bids.mux = &b.mux
+	asks.mux = &b.mux
+	return b
 }
 
 // Midpoint returns the midpoint of the order book.
 func (b *Book) Midpoint() *decimal.Decimal {
-	bid, ask := b.BestBid(), b.BestAsk()
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return midpoint(levelPrice(b.Bids.High), levelPrice(b.Asks.Low))
+}
+
+// Spread returns the relative difference between the bid-ask price.
+func (b *Book) Spread() *decimal.Decimal {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return spread(levelPrice(b.Bids.High), levelPrice(b.Asks.Low))
+}
+
+func levelPrice(l *Level) *decimal.Decimal {
+	if l == nil {
+		return nil
+	}
+	return l.Price
+}
+
+func midpoint(bid, ask *decimal.Decimal) *decimal.Decimal {
 	switch {
 	case bid != nil && ask != nil:
-		return bid.Price.
-			Add(ask.Price).
+		return bid.
+			Add(ask).
 			Mul(decimal.NewFromFloat64(0.5))
 	case bid != nil:
-		return bid.Price
+		return bid
 	case ask != nil:
-		return ask.Price
+		return ask
 	default:
 		return decimal.NewFromInt64(0)
 	}
 }
 
-// Spread returns the relative difference between the bid-ask price.
-func (b *Book) Spread() *decimal.Decimal {
-	bid, ask := b.BestBid(), b.BestAsk()
+func spread(bid, ask *decimal.Decimal) *decimal.Decimal {
 	switch {
 	case bid == nil || ask == nil:
 		return decimal.NewFromInt64(0)
 	default:
-		return ask.Price.
-			SetScale(int64(math.Max(float64(ask.Price.GetScale()), float64(decimal.DefaultScale)))).
-			Sub(bid.Price).
-			Div(ask.Price).
+		return ask.
+			SetScale(int64(math.Max(float64(ask.GetScale()), float64(decimal.DefaultScale)))).
+			Sub(bid).
+			Div(ask).
 			Mul(decimal.NewFromInt64(100))
 	}
 }
@@ -108,50 +135,163 @@ type UpdateOptions struct {
 }
 
 // Update routes the [UpdateOptions] to the correct side of the book and enforces checks to preserve book integrity.
+//
+// The book is locked during the update and the callbacks are called after it is released, so they may read the book.
 func (b *Book) Update(opts *UpdateOptions) {
+	b.mux.Lock()
+	events := b.update(opts, nil)
+	b.mux.Unlock()
+	emit(events)
+}
+
+// update applies an update while the book is locked and returns the callbacks to call afterwards.
+func (b *Book) update(opts *UpdateOptions, events []func()) []func() {
 	switch opts.Direction {
 	case Ask:
 		b.Asks.update(opts)
 	case Bid:
 		b.Bids.update(opts)
 	}
+	if opts.Timestamp.After(b.updated) {
+		b.updated = opts.Timestamp
+	}
 	if b.NoBookCrossing {
-		b.EnforceOrder()
+		events = b.enforceOrder(events)
 	}
 	if b.EnableMaxDepth {
-		b.EnforceDepth()
+		events = b.enforceDepth(events)
 	}
 	if !opts.Silent {
-		b.OnUpdated.Call(opts)
+		events = append(events, func() {
+			b.OnUpdated.Call(opts)
+		})
+	}
+	return events
+}
+
+func emit(events []func()) {
+	for _, event := range events {
+		event()
 	}
 }
 
+// Clear removes all price levels without calling any callbacks, which is required before applying a new snapshot.
+func (b *Book) Clear() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.Bids.clear()
+	b.Asks.clear()
+	b.updated = time.Time{}
+}
+
+// Replace sets the price levels of an L2 book to the given ones and returns the updates applied, which are the differences with the previous levels.
+// Previous levels missing from the given ones are removed before the others are inserted or updated.
+// The callbacks are called for the applied updates as with [Book.Update].
+func (b *Book) Replace(levels []*UpdateOptions) []*UpdateOptions {
+	b.mux.Lock()
+	diffs := b.diff(levels)
+	var events []func()
+	for _, opts := range diffs {
+		events = b.update(opts, events)
+	}
+	b.mux.Unlock()
+	emit(events)
+	return diffs
+}
+
+// diff returns the updates turning the current levels into the given ones while the book is locked.
+func (b *Book) diff(levels []*UpdateOptions) []*UpdateOptions {
+	keys := map[BookDirection]map[string]bool{Bid: {}, Ask: {}}
+	var diffs []*UpdateOptions
+	for _, opts := range levels {
+		side := b.Bids
+		if opts.Direction == Ask {
+			side = b.Asks
+		}
+		key := opts.Price.Rat().RatString()
+		if opts.Quantity.Sign() <= 0 || keys[side.Direction][key] {
+			continue
+		}
+		keys[side.Direction][key] = true
+		if level := side.Get(opts.Price); level != nil && level.Quantity.Cmp(opts.Quantity) == 0 {
+			continue
+		}
+		diffs = append(diffs, opts)
+	}
+	var removed []*UpdateOptions
+	now := time.Now()
+	for _, side := range []*Side{b.Bids, b.Asks} {
+		for level := range side.levels(0) {
+			if keys[side.Direction][level.Price.Rat().RatString()] {
+				continue
+			}
+			removed = append(removed, &UpdateOptions{
+				Direction: side.Direction,
+				Price:     level.Price,
+				Quantity:  decimal.NewFromInt64(0),
+				Timestamp: now,
+			})
+		}
+	}
+	return append(removed, diffs...)
+}
+
+// View calls f while the book is locked for reading, which is required to walk the levels of [Side] directly.
+// The book must not be updated within f.
+func (b *Book) View(f func(b *Book)) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	f(b)
+}
+
 // BestBid returns the highest bid price level.
+//
+// The level is shared with the book and changes with later updates. Use [Book.Snapshot] for consistent reads.
 func (b *Book) BestBid() *Level {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
 	return b.Bids.High
 }
 
 // BestAsk returns the lowest ask price level.
+//
+// The level is shared with the book and changes with later updates. Use [Book.Snapshot] for consistent reads.
 func (b *Book) BestAsk() *Level {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
 	return b.Asks.Low
 }
 
 // WorstAsk returns the highest ask price level.
 func (b *Book) WorstAsk() *Level {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
 	return b.Asks.High
 }
 
 // WorstBid returns the lowest bid price level.
 func (b *Book) WorstBid() *Level {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
 	return b.Bids.Low
 }
 
 // EnforceOrder check whether the bid is greater or equal to the ask and attempts to remove older conflicting price levels.
 func (b *Book) EnforceOrder() {
-	for bid, ask := b.BestBid(), b.BestAsk(); bid != nil && ask != nil && bid.Price.Cmp(ask.Price) >= 0; bid, ask = b.BestBid(), b.BestAsk() {
-		b.OnBookCrossed.Call(&CrossedResult{
+	b.mux.Lock()
+	events := b.enforceOrder(nil)
+	b.mux.Unlock()
+	emit(events)
+}
+
+func (b *Book) enforceOrder(events []func()) []func() {
+	for bid, ask := b.Bids.High, b.Asks.Low; bid != nil && ask != nil && bid.Price.Cmp(ask.Price) >= 0; bid, ask = b.Bids.High, b.Asks.Low {
+		crossed := &CrossedResult{
 			Bid: bid,
 			Ask: ask,
+		}
+		events = append(events, func() {
+			b.OnBookCrossed.Call(crossed)
 		})
 		var input *UpdateOptions
 		if bid.Timestamp.After(ask.Timestamp) {
@@ -169,8 +309,9 @@ func (b *Book) EnforceOrder() {
 				Timestamp: time.Now(),
 			}
 		}
-		b.Update(input)
+		events = b.update(input, events)
 	}
+	return events
 }
 
 type CrossedResult struct {
@@ -186,33 +327,46 @@ type MaxDepthExceededResult struct {
 
 // EnforceDepth checks for price level count that exceed the max depth, then removes the worst price levels until compliant.
 func (b *Book) EnforceDepth() {
-	for len(b.Bids.Levels) > b.MaxDepth {
-		b.OnMaxDepthExceeded.Call(&MaxDepthExceededResult{
+	b.mux.Lock()
+	events := b.enforceDepth(nil)
+	b.mux.Unlock()
+	emit(events)
+}
+
+func (b *Book) enforceDepth(events []func()) []func() {
+	for b.Bids.Len() > b.MaxDepth {
+		exceeded := &MaxDepthExceededResult{
 			Side:         Bid,
-			CurrentDepth: len(b.Bids.Levels),
+			CurrentDepth: b.Bids.Len(),
 			MaxDepth:     b.MaxDepth,
-			Worst:        b.WorstBid(),
+			Worst:        b.Bids.Low,
+		}
+		events = append(events, func() {
+			b.OnMaxDepthExceeded.Call(exceeded)
 		})
-		b.Update(&UpdateOptions{
+		events = b.update(&UpdateOptions{
 			Direction: Bid,
-			Price:     b.WorstBid().Price,
+			Price:     b.Bids.Low.Price,
 			Quantity:  decimal.NewFromInt64(0),
 			Timestamp: time.Now(),
-		})
+		}, events)
 	}
-	for len(b.Asks.Levels) > b.MaxDepth {
-		b.OnMaxDepthExceeded.Call(&MaxDepthExceededResult{
+	for b.Asks.Len() > b.MaxDepth {
+		exceeded := &MaxDepthExceededResult{
 			Side:         Ask,
-			CurrentDepth: len(b.Asks.Levels),
+			CurrentDepth: b.Asks.Len(),
 			MaxDepth:     b.MaxDepth,
-			Worst:        b.WorstAsk(),
+			Worst:        b.Asks.High,
+		}
+		events = append(events, func() {
+			b.OnMaxDepthExceeded.Call(exceeded)
 		})
-		b.Update(&UpdateOptions{
+		events = b.update(&UpdateOptions{
 			Direction: Ask,
-			Price:     b.WorstAsk().Price,
+			Price:     b.Asks.High.Price,
 			Quantity:  decimal.NewFromInt64(0),
 			Timestamp: time.Now(),
-		})
-
+		}, events)
 	}
+	return events
 }
@@ -0,0 +1,119 @@
+package book
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+func TestBookConcurrency(t *testing.T) {
+	b := New()
+	b.OnUpdated.Recurring(func(e *callback.Event[*UpdateOptions]) {
+		b.Midpoint()
+	})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			b.Update(&UpdateOptions{
+				Direction: Bid,
+				Price:     decimal.NewFromInt64(int64(100 + i%10)),
+				Quantity:  decimal.NewFromInt64(int64(i % 3)),
+				Timestamp: time.Now(),
+			})
+			b.Update(&UpdateOptions{
+				Direction: Ask,
+				Price:     decimal.NewFromInt64(int64(111 + i%10)),
+				Quantity:  decimal.NewFromInt64(int64(i % 4)),
+				Timestamp: time.Now(),
+			})
+		}
+	}()
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				snapshot := b.Snapshot(5)
+				if len(snapshot.Bids) > 5 || len(snapshot.Asks) > 5 {
+					t.Errorf("expected at most 5 levels, got %d bids and %d asks", len(snapshot.Bids), len(snapshot.Asks))
+				}
+				b.Spread()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBookSnapshot(t *testing.T) {
+	b := New()
+	for i, price := range []int64{101, 103, 102} {
+		b.Update(&UpdateOptions{
+			Direction: Ask,
+			ID:        string(rune('a' + i)),
+			Price:     decimal.NewFromInt64(price),
+			Quantity:  decimal.NewFromInt64(1),
+			Timestamp: time.Unix(int64(i), 0),
+		})
+	}
+	b.Update(&UpdateOptions{
+		Direction: Bid,
+		Price:     decimal.NewFromInt64(99),
+		Quantity:  decimal.NewFromInt64(2),
+		Timestamp: time.Unix(3, 0),
+	})
+	snapshot := b.Snapshot(2)
+	if len(snapshot.Asks) != 2 || snapshot.Asks[0].Price.Int64() != 101 || snapshot.Asks[1].Price.Int64() != 102 {
+		t.Errorf("expected asks 101 and 102, got %+v", snapshot.Asks)
+	}
+	if len(snapshot.Asks[0].Orders) != 1 || snapshot.Asks[0].Orders[0].ID != "a" {
+		t.Errorf("expected order a at 101, got %+v", snapshot.Asks[0].Orders)
+	}
+	if !snapshot.Timestamp.Equal(time.Unix(3, 0)) {
+		t.Errorf("expected timestamp of the last update, got %s", snapshot.Timestamp)
+	}
+	b.Update(&UpdateOptions{
+		Direction: Ask,
+		ID:        "a",
+		Price:     decimal.NewFromInt64(101),
+		Quantity:  decimal.NewFromInt64(0),
+		Timestamp: time.Unix(4, 0),
+	})
+	if snapshot.BestAsk().Price.Int64() != 101 || b.BestAsk().Price.Int64() != 102 {
+		t.Errorf("expected the snapshot to be unaffected by updates")
+	}
+	if midpoint := snapshot.Midpoint(); midpoint.Cmp(decimal.NewFromInt64(100)) != 0 {
+		t.Errorf("expected midpoint 100, got %s", midpoint)
+	}
+}
+
+func TestBookReplace(t *testing.T) {
+	b := newTestBook(t, [][2]string{{"99", "1"}, {"98", "2"}}, [][2]string{{"101", "1"}})
+	var updated []*UpdateOptions
+	b.OnUpdated.Recurring(func(e *callback.Event[*UpdateOptions]) {
+		updated = append(updated, e.Data)
+	})
+	level := func(direction BookDirection, price, quantity int64) *UpdateOptions {
+		return &UpdateOptions{
+			Direction: direction,
+			Price:     decimal.NewFromInt64(price),
+			Quantity:  decimal.NewFromInt64(quantity),
+			Timestamp: time.Unix(2, 0),
+		}
+	}
+	diffs := b.Replace([]*UpdateOptions{level(Bid, 99, 1), level(Bid, 97, 3), level(Ask, 101, 2)})
+	if len(diffs) != 3 || len(updated) != 3 {
+		t.Fatalf("expected 3 updates, got %d diffs and %d events", len(diffs), len(updated))
+	}
+	if diffs[0].Price.Int64() != 98 || diffs[0].Quantity.Sign() != 0 {
+		t.Errorf("expected the removal of 98 first, got %+v", diffs[0])
+	}
+	snapshot := b.Snapshot(0)
+	if len(snapshot.Bids) != 2 || snapshot.Bids[1].Price.Int64() != 97 || snapshot.Asks[0].Quantity.Int64() != 2 {
+		t.Errorf("expected bids 99 and 97 and ask 101 of 2, got %+v", snapshot)
+	}
+}
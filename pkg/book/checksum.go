@@ -35,7 +35,8 @@ func (b *Book) L2Checksum(checksum string) *ChecksumResult {
 		Level:          2,
 		ServerChecksum: checksum,
 	}
-	cursor := b.BestAsk()
+	b.mux.RLock()
+	cursor := b.Asks.Low
 	for range 10 {
 		if cursor == nil {
 			break
@@ -52,7 +53,7 @@ func (b *Book) L2Checksum(checksum string) *ChecksumResult {
 		result.Asks += concatenated
 		cursor = cursor.Higher
 	}
-	cursor = b.BestBid()
+	cursor = b.Bids.High
 	for range 10 {
 		if cursor == nil {
 			break
@@ -69,6 +70,7 @@ func (b *Book) L2Checksum(checksum string) *ChecksumResult {
 		result.Bids += concatenated
 		cursor = cursor.Lower
 	}
+	b.mux.RUnlock()
 	result.LocalChecksum = fmt.Sprint(crc32.Checksum([]byte(result.Asks+result.Bids), crc32.IEEETable))
 	if result.LocalChecksum == result.ServerChecksum {
 		result.Match = true
@@ -85,7 +87,8 @@ func (b *Book) L3Checksum(checksum string) *ChecksumResult {
 		Level:          3,
 		ServerChecksum: checksum,
 	}
-	cursor := b.BestAsk()
+	b.mux.RLock()
+	cursor := b.Asks.Low
 	for range 10 {
 		if cursor == nil {
 			break
@@ -105,7 +108,7 @@ func (b *Book) L3Checksum(checksum string) *ChecksumResult {
 		}
 		cursor = cursor.Higher
 	}
-	cursor = b.BestBid()
+	cursor = b.Bids.High
 	for range 10 {
 		if cursor == nil {
 			break
@@ -125,6 +128,7 @@ func (b *Book) L3Checksum(checksum string) *ChecksumResult {
 		}
 		cursor = cursor.Lower
 	}
+	b.mux.RUnlock()
 	result.LocalChecksum = fmt.Sprint(crc32.Checksum([]byte(result.Asks+result.Bids), crc32.IEEETable))
 	if result.LocalChecksum == result.ServerChecksum {
 		result.Match = true
@@ -0,0 +1,203 @@
+package book
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// All returns an iterator over copies of the price levels from the best price, which is the highest bid or the lowest ask.
+//
+// The levels are copied when the iteration starts, so the book is not locked within the loop.
+func (s *Side) All() iter.Seq[*LevelSnapshot] {
+	return s.Top(0)
+}
+
+// Top returns an iterator over copies of up to n price levels from the best price, or all of them if n is not positive.
+//
+// The levels are copied when the iteration starts, so the book is not locked within the loop.
+func (s *Side) Top(n int) iter.Seq[*LevelSnapshot] {
+	return func(yield func(*LevelSnapshot) bool) {
+		if s.mux != nil {
+			s.mux.RLock()
+		}
+		levels := snapshotSide(s, n)
+		if s.mux != nil {
+			s.mux.RUnlock()
+		}
+		for _, level := range levels {
+			if !yield(level) {
+				return
+			}
+		}
+	}
+}
+
+// levels iterates over up to n price levels from the best price without locking.
+func (s *Side) levels(n int) iter.Seq[*Level] {
+	return func(yield func(*Level) bool) {
+		cursor, next := s.Low, func(l *Level) *Level { return l.Higher }
+		if s.Direction == Bid {
+			cursor, next = s.High, func(l *Level) *Level { return l.Lower }
+		}
+		for i := 0; cursor != nil && (n <= 0 || i < n); i++ {
+			if !yield(cursor) {
+				return
+			}
+			cursor = next(cursor)
+		}
+	}
+}
+
+// Depth is the cumulative liquidity of a range of price levels.
+type Depth struct {
+	Levels   int              `json:"levels,omitempty"`
+	Quantity *decimal.Decimal `json:"quantity,omitempty"`
+	Notional *decimal.Decimal `json:"notional,omitempty"`
+}
+
+func (d *Depth) add(level *Level) {
+	d.Levels++
+	d.Quantity = d.Quantity.Add(level.Quantity)
+	d.Notional = d.Notional.Add(notional(level.Price, level.Quantity))
+}
+
+// notional returns the exact product of a price and a quantity.
+func notional(price, quantity *decimal.Decimal) *decimal.Decimal {
+	return price.SetScale(price.GetScale() + quantity.GetScale()).Mul(quantity)
+}
+
+func newDepth() *Depth {
+	return &Depth{
+		Quantity: decimal.NewFromInt64(0),
+		Notional: decimal.NewFromInt64(0),
+	}
+}
+
+// Depth returns the cumulative quantity and notional of up to n price levels from the best price, or all of them if n is not positive.
+func (s *Side) Depth(n int) *Depth {
+	if s.mux != nil {
+		s.mux.RLock()
+		defer s.mux.RUnlock()
+	}
+	depth := newDepth()
+	for level := range s.levels(n) {
+		depth.add(level)
+	}
+	return depth
+}
+
+// DepthWithin returns the cumulative quantity and notional of the price levels from the best price up to and including limit.
+func (s *Side) DepthWithin(limit *decimal.Decimal) *Depth {
+	if s.mux != nil {
+		s.mux.RLock()
+		defer s.mux.RUnlock()
+	}
+	return s.depthWithin(limit)
+}
+
+func (s *Side) depthWithin(limit *decimal.Decimal) *Depth {
+	depth := newDepth()
+	for level := range s.levels(0) {
+		if cmp := level.Price.Cmp(limit); s.Direction == Bid && cmp < 0 || s.Direction != Bid && cmp > 0 {
+			break
+		}
+		depth.add(level)
+	}
+	return depth
+}
+
+// DepthWithinPercent returns the cumulative depth of each side within the given percentage of the midpoint.
+func (b *Book) DepthWithinPercent(percent *decimal.Decimal) (bids *Depth, asks *Depth) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	mid := midpoint(levelPrice(b.Bids.High), levelPrice(b.Asks.Low))
+	offset := mid.Mul(percent).Div(decimal.NewFromInt64(100))
+	return b.Bids.depthWithin(mid.Sub(offset)), b.Asks.depthWithin(mid.Add(offset))
+}
+
+// Bucket is the aggregation of the price levels within a price range of one tick.
+type Bucket struct {
+	Price    *decimal.Decimal `json:"price,omitempty"`
+	Quantity *decimal.Decimal `json:"quantity,omitempty"`
+	Levels   int              `json:"levels,omitempty"`
+}
+
+// Aggregate groups the price levels into buckets of the given tick size from the best price and returns up to n buckets, or all of them if n is not positive.
+// Bid prices are rounded down and ask prices are rounded up to a multiple of the tick.
+func (s *Side) Aggregate(tick *decimal.Decimal, n int) []*Bucket {
+	tickKey := priceKey(tick)
+	if tickKey <= 0 {
+		return nil
+	}
+	if s.mux != nil {
+		s.mux.RLock()
+		defer s.mux.RUnlock()
+	}
+	var buckets []*Bucket
+	var current int64
+	for level := range s.levels(0) {
+		key := level.key - level.key%tickKey
+		if level.key%tickKey < 0 {
+			key -= tickKey
+		}
+		if s.Direction != Bid && key != level.key {
+			key += tickKey
+		}
+		if len(buckets) == 0 || key != current {
+			if n > 0 && len(buckets) == n {
+				break
+			}
+			current = key
+			buckets = append(buckets, &Bucket{
+				Price:    decimal.NewFromFixedPoint(key, KeyScale).SetScale(tick.GetScale()),
+				Quantity: decimal.NewFromInt64(0),
+			})
+		}
+		bucket := buckets[len(buckets)-1]
+		bucket.Quantity = bucket.Quantity.Add(level.Quantity)
+		bucket.Levels++
+	}
+	return buckets
+}
+
+// WriteJSON encodes a [Snapshot] of up to n price levels per side as JSON.
+func (b *Book) WriteJSON(w io.Writer, n int) error {
+	if err := json.NewEncoder(w).Encode(b.Snapshot(n)); err != nil {
+		return fmt.Errorf("json encode: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes up to n price levels per side as CSV with the columns side, price, quantity and timestamp.
+func (b *Book) WriteCSV(w io.Writer, n int) error {
+	return b.Snapshot(n).WriteCSV(w)
+}
+
+// WriteCSV writes the price levels as CSV with the columns side, price, quantity and timestamp.
+func (s *Snapshot) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"side", "price", "quantity", "timestamp"}}
+	for _, side := range []struct {
+		direction BookDirection
+		levels    []*LevelSnapshot
+	}{{Bid, s.Bids}, {Ask, s.Asks}} {
+		for _, level := range side.levels {
+			records = append(records, []string{
+				string(side.direction),
+				level.Price.String(),
+				level.Quantity.String(),
+				level.Timestamp.Format(time.RFC3339Nano),
+			})
+		}
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("csv write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package book
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// newTestBook constructs a book from price and quantity pairs.
+func newTestBook(t *testing.T, bids, asks [][2]string) *Book {
+	b := New()
+	for direction, levels := range map[BookDirection][][2]string{Bid: bids, Ask: asks} {
+		for _, level := range levels {
+			price, err := decimal.NewFromString(level[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			quantity, err := decimal.NewFromString(level[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.Update(&UpdateOptions{
+				Direction: direction,
+				Price:     price,
+				Quantity:  quantity,
+				Timestamp: time.Unix(1, 0),
+			})
+		}
+	}
+	return b
+}
+
+func TestBookDepth(t *testing.T) {
+	b := newTestBook(t,
+		[][2]string{{"99.5", "1"}, {"99.2", "2"}, {"98.7", "3"}, {"97", "4"}},
+		[][2]string{{"100.5", "1"}, {"100.6", "2"}, {"101.4", "3"}},
+	)
+	var bids []string
+	for level := range b.Bids.Top(3) {
+		bids = append(bids, level.Price.String())
+	}
+	if expected := []string{"99.5", "99.2", "98.7"}; !slices.Equal(bids, expected) {
+		t.Errorf("expected %v, got %v", expected, bids)
+	}
+	var asks int
+	for range b.Asks.All() {
+		asks++
+	}
+	if asks != 3 {
+		t.Errorf("expected 3 asks, got %d", asks)
+	}
+	if depth := b.Bids.Depth(2); depth.Levels != 2 || depth.Quantity.Int64() != 3 || depth.Notional.Cmp(decimal.NewFromFloat64(297.9)) != 0 {
+		t.Errorf("expected 2 levels with quantity 3 and notional 297.9, got %+v", depth)
+	}
+	bidDepth, askDepth := b.DepthWithinPercent(decimal.NewFromInt64(1))
+	if bidDepth.Levels != 2 || askDepth.Levels != 2 {
+		t.Errorf("expected 2 levels on each side within 1%%, got %d and %d", bidDepth.Levels, askDepth.Levels)
+	}
+	buckets := b.Asks.Aggregate(decimal.NewFromInt64(1), 0)
+	if len(buckets) != 2 || buckets[0].Price.Int64() != 101 || buckets[0].Quantity.Int64() != 3 || buckets[1].Price.Int64() != 102 {
+		t.Errorf("expected ask buckets 101 and 102, got %+v", buckets)
+	}
+	buckets = b.Bids.Aggregate(decimal.NewFromInt64(1), 2)
+	if len(buckets) != 2 || buckets[0].Price.Int64() != 99 || buckets[0].Levels != 2 || buckets[1].Price.Int64() != 98 {
+		t.Errorf("expected bid buckets 99 and 98, got %+v", buckets)
+	}
+	asks = 0
+	for level := range b.Asks.All() {
+		asks++
+		b.Update(&UpdateOptions{Direction: Ask, Price: level.Price, Quantity: decimal.NewFromInt64(0)})
+		if best := b.BestAsk(); asks < 3 && best.Price.Cmp(level.Price) <= 0 {
+			t.Errorf("expected %s to be removed within the loop, got %s", level.Price, best.Price)
+		}
+	}
+	if asks != 3 || b.Asks.Len() != 0 {
+		t.Errorf("expected 3 asks to be removed, got %d and %d left", asks, b.Asks.Len())
+	}
+}
+
+func TestBookExport(t *testing.T) {
+	b := newTestBook(t, [][2]string{{"99.5", "1"}, {"99.2", "2"}}, [][2]string{{"100.5", "1"}})
+	var buffer bytes.Buffer
+	if err := b.WriteCSV(&buffer, 1); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "bid,99.5,1,") || !strings.HasPrefix(lines[2], "ask,100.5,1,") {
+		t.Errorf("expected a header with the best bid and ask, got %q", lines)
+	}
+	buffer.Reset()
+	if err := b.WriteJSON(&buffer, 0); err != nil {
+		t.Fatal(err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(buffer.Bytes(), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Bids) != 2 || len(snapshot.Asks) != 1 || snapshot.Bids[1].Price.String() != "99.2" {
+		t.Errorf("expected all levels in the JSON snapshot, got %+v", snapshot)
+	}
+}
@@ -0,0 +1,155 @@
+package book
+
+import (
+	"fmt"
+
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// SizeFormatter rounds a quantity to the lot size of a symbol, which is implemented by spot.Normalizer and derivatives.Normalizer.
+type SizeFormatter interface {
+	FormatSize(symbol string, v *decimal.Decimal) (*decimal.Decimal, error)
+}
+
+// Execution is the estimated result of a market order walking the book.
+type Execution struct {
+	// Side of the order, so a bid buys from the asks and an ask sells to the bids.
+	Direction BookDirection `json:"direction,omitempty"`
+
+	// Quantity requested after rounding to the lot size.
+	Requested *decimal.Decimal `json:"requested,omitempty"`
+
+	// Quantity and notional filled from the book.
+	Quantity *decimal.Decimal `json:"quantity,omitempty"`
+	Notional *decimal.Decimal `json:"notional,omitempty"`
+
+	// Volume-weighted average and last filled prices, or nil if nothing is filled.
+	AveragePrice *decimal.Decimal `json:"averagePrice,omitempty"`
+	WorstPrice   *decimal.Decimal `json:"worstPrice,omitempty"`
+
+	// Midpoint before the order and the percentage of the average price away from it, which is positive when it is a cost.
+	Midpoint *decimal.Decimal `json:"midpoint,omitempty"`
+	Slippage *decimal.Decimal `json:"slippage,omitempty"`
+
+	// Number of price levels consumed, including a partially filled one.
+	Levels int `json:"levels,omitempty"`
+
+	// Whether the book has enough liquidity to fill the requested quantity.
+	Complete bool `json:"complete,omitempty"`
+}
+
+// SimulateMarket estimates the fills of a market order of the given quantity without modifying the book.
+// The direction is the side of the order, so a [Bid] buys from the asks and an [Ask] sells to the bids.
+//
+// If [Book.Normalizer] is set, the quantity is first rounded down to the lot size of the instrument.
+func (b *Book) SimulateMarket(direction BookDirection, quantity *decimal.Decimal) (*Execution, error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	quantity, err := b.lots(quantity)
+	if err != nil {
+		return nil, err
+	}
+	return b.simulate(direction, quantity), nil
+}
+
+// SimulateMarketNotional estimates the fills of a market order spending up to the given amount of the quote currency.
+//
+// If [Book.Normalizer] is set, the quantity is rounded down to the lot size of the instrument.
+func (b *Book) SimulateMarketNotional(direction BookDirection, amount *decimal.Decimal) (*Execution, error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	remaining := amount.SetScale(max(amount.GetScale(), decimal.DefaultScale))
+	quantity := decimal.NewFromInt64(0)
+	for level := range b.opposite(direction).levels(0) {
+		if remaining.Sign() <= 0 {
+			break
+		}
+		cost := notional(level.Price, level.Quantity)
+		if cost.Cmp(remaining) <= 0 {
+			quantity = quantity.Add(level.Quantity)
+			remaining = remaining.Sub(cost)
+			continue
+		}
+		quantity = quantity.Add(remaining.Div(level.Price))
+		break
+	}
+	quantity, err := b.lots(quantity)
+	if err != nil {
+		return nil, err
+	}
+	return b.simulate(direction, quantity), nil
+}
+
+// QuantityForPrice returns the quantity that an order can fill up to and including the limit price.
+// The direction is the side of the order, so a [Bid] buys the asks at or below the limit and an [Ask] sells to the bids at or above it.
+//
+// If [Book.Normalizer] is set, the quantity is rounded down to the lot size of the instrument.
+func (b *Book) QuantityForPrice(direction BookDirection, limit *decimal.Decimal) (*decimal.Decimal, error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return b.lots(b.opposite(direction).depthWithin(limit).Quantity)
+}
+
+// opposite returns the side of the book consumed by an order of the given direction.
+func (b *Book) opposite(direction BookDirection) *Side {
+	if direction == Ask {
+		return b.Bids
+	}
+	return b.Asks
+}
+
+// lots rounds a quantity down to the lot size of the instrument if [Book.Normalizer] is set.
+func (b *Book) lots(quantity *decimal.Decimal) (*decimal.Decimal, error) {
+	if b.Normalizer == nil {
+		return quantity, nil
+	}
+	formatted, err := b.Normalizer.FormatSize(b.Name, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("format size: %w", err)
+	}
+	if formatted.Cmp(quantity) > 0 {
+		formatted = formatted.Sub(decimal.NewFromFixedPoint(max(formatted.GetIncrement(), 1), formatted.GetScale()))
+	}
+	return formatted, nil
+}
+
+// simulate walks the levels consumed by an order while the book is locked.
+func (b *Book) simulate(direction BookDirection, quantity *decimal.Decimal) *Execution {
+	execution := &Execution{
+		Direction: direction,
+		Requested: quantity,
+		Quantity:  decimal.NewFromInt64(0),
+		Notional:  decimal.NewFromInt64(0),
+		Midpoint:  midpoint(levelPrice(b.Bids.High), levelPrice(b.Asks.Low)),
+	}
+	remaining := quantity.SetScale(max(quantity.GetScale(), decimal.DefaultScale))
+	for level := range b.opposite(direction).levels(0) {
+		if remaining.Sign() <= 0 {
+			break
+		}
+		fill := level.Quantity
+		if fill.Cmp(remaining) > 0 {
+			fill = remaining
+		}
+		execution.Levels++
+		execution.Quantity = execution.Quantity.Add(fill)
+		execution.Notional = execution.Notional.Add(notional(level.Price, fill))
+		execution.WorstPrice = level.Price
+		remaining = remaining.Sub(fill)
+	}
+	execution.Complete = remaining.Sign() <= 0
+	if execution.Quantity.Sign() <= 0 {
+		return execution
+	}
+	execution.AveragePrice = execution.Notional.Div(execution.Quantity)
+	if execution.Midpoint.Sign() > 0 {
+		difference := execution.AveragePrice.Sub(execution.Midpoint)
+		if direction == Ask {
+			difference = execution.Midpoint.Sub(execution.AveragePrice)
+		}
+		execution.Slippage = difference.
+			Div(execution.Midpoint).
+			Mul(decimal.NewFromInt64(100))
+	}
+	return execution
+}
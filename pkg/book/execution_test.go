@@ -0,0 +1,63 @@
+package book
+
+import (
+	"testing"
+
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// lotFormatter rounds quantities to a lot size of 0.5.
+type lotFormatter struct{}
+
+func (lotFormatter) FormatSize(symbol string, v *decimal.Decimal) (*decimal.Decimal, error) {
+	return v.SetScale(1).SetIncrement(5), nil
+}
+
+func TestBookSimulateMarket(t *testing.T) {
+	b := newTestBook(t,
+		[][2]string{{"99.5", "1"}, {"99.0", "2"}},
+		[][2]string{{"100.5", "1"}, {"101.0", "2"}, {"102.0", "4"}},
+	)
+	execution, err := b.SimulateMarket(Bid, decimal.NewFromInt64(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !execution.Complete || execution.Levels != 2 || execution.WorstPrice.Cmp(decimal.NewFromInt64(101)) != 0 {
+		t.Errorf("expected a complete fill over 2 levels up to 101, got %+v", execution)
+	}
+	if execution.AveragePrice.Cmp(decimal.NewFromFloat64(100.75)) != 0 {
+		t.Errorf("expected average price 100.75, got %s", execution.AveragePrice)
+	}
+	if execution.Slippage.Cmp(decimal.NewFromFloat64(0.75)) != 0 {
+		t.Errorf("expected slippage 0.75%%, got %s", execution.Slippage)
+	}
+	execution, err = b.SimulateMarket(Ask, decimal.NewFromInt64(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execution.Complete || execution.Quantity.Int64() != 3 || execution.Notional.Cmp(decimal.NewFromFloat64(297.5)) != 0 {
+		t.Errorf("expected a partial fill of 3 for 297.5, got %+v", execution)
+	}
+	execution, err = b.SimulateMarketNotional(Bid, decimal.NewFromInt64(252))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execution.Quantity.Cmp(decimal.NewFromFloat64(2.5)) != 0 || execution.Levels != 2 {
+		t.Errorf("expected a fill of 2.5 over 2 levels, got %+v", execution)
+	}
+	quantity, err := b.QuantityForPrice(Bid, decimal.NewFromInt64(101))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quantity.Int64() != 3 {
+		t.Errorf("expected 3 up to 101, got %s", quantity)
+	}
+	b.Normalizer = lotFormatter{}
+	execution, err = b.SimulateMarketNotional(Bid, decimal.NewFromInt64(275))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execution.Requested.Cmp(decimal.NewFromFloat64(2.5)) != 0 || execution.Quantity.Cmp(decimal.NewFromFloat64(2.5)) != 0 {
+		t.Errorf("expected the quantity rounded down to 2.5, got %+v", execution)
+	}
+}
@@ -2,6 +2,7 @@ package book
 
 import (
 	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,9 +16,13 @@ type Level struct {
 	Timestamp  time.Time        `json:"time,omitempty"`
 	Lower      *Level           `json:"-"`
 	Higher     *Level           `json:"-"`
+	key        int64
+	exact      bool
+	next       []*Level
 	orders     map[string]*Order
 	queue      []*Order
 	queueDirty atomic.Bool
+	queueMux   sync.Mutex
 }
 
 // NewLevel constructs a new [Level] struct with default values.
@@ -64,6 +69,8 @@ func (l *Level) update(opts *UpdateOptions) {
 
 // Queue returns a list of orders arranged by time priority.
 func (l *Level) Queue() []*Order {
+	l.queueMux.Lock()
+	defer l.queueMux.Unlock()
 	if !l.queueDirty.Load() {
 		return l.queue
 	}
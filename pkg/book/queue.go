@@ -0,0 +1,54 @@
+package book
+
+import (
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// QueuePosition is the place of an order in the time priority queue of its L3 price level.
+type QueuePosition struct {
+	OrderID   string           `json:"orderId,omitempty"`
+	Direction BookDirection    `json:"direction,omitempty"`
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	Quantity  *decimal.Decimal `json:"quantity,omitempty"`
+
+	// Number of orders and their cumulative quantity before the order.
+	Index int              `json:"index"`
+	Ahead *decimal.Decimal `json:"ahead,omitempty"`
+
+	// Number of orders and total quantity of the price level.
+	Orders        int              `json:"orders,omitempty"`
+	LevelQuantity *decimal.Decimal `json:"levelQuantity,omitempty"`
+}
+
+// QueuePosition returns the position of an order in the queue of the price level, or nil if the order is not in the book.
+func (b *Book) QueuePosition(direction BookDirection, price *decimal.Decimal, orderID string) *QueuePosition {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	side := b.Bids
+	if direction == Ask {
+		side = b.Asks
+	}
+	level := side.Get(price)
+	if level == nil {
+		return nil
+	}
+	queue := level.Queue()
+	ahead := decimal.NewFromInt64(0)
+	for i, order := range queue {
+		if order.ID != orderID {
+			ahead = ahead.Add(order.Quantity)
+			continue
+		}
+		return &QueuePosition{
+			OrderID:       orderID,
+			Direction:     side.Direction,
+			Price:         level.Price.Copy(),
+			Quantity:      order.Quantity.Copy(),
+			Index:         i,
+			Ahead:         ahead,
+			Orders:        len(queue),
+			LevelQuantity: level.Quantity.Copy(),
+		}
+	}
+	return nil
+}
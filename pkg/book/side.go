@@ -1,131 +1,233 @@
 package book
 
 import (
+	"cmp"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+
 	"github.com/krakenfx/api-go/v2/pkg/decimal"
 )
 
+// KeyScale is the number of decimals of the fixed-point integer keys of price levels.
+// Prices with more decimals or an absolute value above 9.2e8 are rounded and clamped, and compared exactly when their keys are equal.
+const KeyScale = 10
+
+// Maximum number of skip list layers, which supports about 4^16 levels efficiently.
+const maxHeight = 16
+
 // Side encompasses the price levels in one side of the book.
+//
+// Levels are stored in a skip list ordered by price, which is navigated from High and Low through [Level.Lower] and [Level.Higher].
 type Side struct {
-	Direction BookDirection     `json:"direction,omitempty"`
-	High      *Level            `json:"high,omitempty"`
-	Low       *Level            `json:"low,omitempty"`
-	Last      *Level            `json:"last,omitempty"`
-	Levels    map[string]*Level `json:"levels,omitempty"`
+	Direction BookDirection `json:"direction,omitempty"`
+	High      *Level        `json:"high,omitempty"`
+	Low       *Level        `json:"low,omitempty"`
+	Last      *Level        `json:"last,omitempty"`
+	head      [maxHeight]*Level
+	height    int
+	length    int
+
+	// Lock of the book owning the side, if any.
+	mux *sync.RWMutex
 }
 
 // NewSide constructs a new [Side] with default values.
 func NewSide() *Side {
-	return &Side{
-		Levels: make(map[string]*Level),
+	return &Side{}
+}
+
+// position is the place of a price in the skip list.
+// Prices whose key is not exact are ordered by their key first and by their value within the same key.
+type position struct {
+	key   int64
+	exact bool
+	price *decimal.Decimal
+}
+
+// positionOf returns the position of a price.
+func positionOf(price *decimal.Decimal) position {
+	p := position{price: price}
+	if p.key, p.exact = price.FixedPoint(KeyScale); p.exact {
+		return p
+	}
+	rounded := price.SetScale(KeyScale).RawBigInt()
+	switch {
+	case rounded.IsInt64():
+		p.key = rounded.Int64()
+	case rounded.Sign() > 0:
+		p.key = math.MaxInt64
+	default:
+		p.key = math.MinInt64
+	}
+	return p
+}
+
+// priceKey converts a price to the key of its level.
+func priceKey(price *decimal.Decimal) int64 {
+	return positionOf(price).key
+}
+
+// compare orders a level relative to a position.
+func (l *Level) compare(p position) int {
+	if c := cmp.Compare(l.key, p.key); c != 0 || l.exact && p.exact {
+		return c
 	}
+	return l.Price.Cmp(p.price)
 }
 
-// FindAdjacent finds the nearest price level close to the given price.
-func (s *Side) FindAdjacent(price *decimal.Decimal) *Level {
-	if s.High == nil || s.Low == nil {
-		return nil
+// next returns the following level of l in a layer or the first level of the layer if l is nil.
+func (s *Side) next(l *Level, layer int) *Level {
+	if l == nil {
+		return s.head[layer]
 	}
-	if price.Cmp(s.High.Price) > 0 {
-		return s.High
+	return l.next[layer]
+}
+
+// search returns the lowest level at or above the given position.
+// If predecessors is not nil, it receives the last level before the position in each layer.
+func (s *Side) search(p position, predecessors *[maxHeight]*Level) *Level {
+	var prev *Level
+	for layer := s.height - 1; layer >= 0; layer-- {
+		for next := s.next(prev, layer); next != nil && next.compare(p) < 0; next = s.next(prev, layer) {
+			prev = next
+		}
+		if predecessors != nil {
+			predecessors[layer] = prev
+		}
 	}
-	if price.Cmp(s.Low.Price) < 0 {
-		return s.Low
+	return s.next(prev, 0)
+}
+
+// Len returns the number of price levels.
+func (s *Side) Len() int {
+	return s.length
+}
+
+// Get returns the price level at the given price or nil if there is none.
+func (s *Side) Get(price *decimal.Decimal) *Level {
+	p := positionOf(price)
+	if level := s.search(p, nil); level != nil && level.compare(p) == 0 {
+		return level
 	}
-	highDistance := s.High.Price.Sub(price)
-	lowDistance := price.Sub(s.Low.Price)
-	if highDistance.Cmp(lowDistance) == 1 {
-		return s.FindAdjacentBelow(price)
-	} else {
-		return s.FindAdjacentAbove(price)
+	return nil
+}
+
+// FindAdjacent finds the nearest price level close to the given price.
+func (s *Side) FindAdjacent(price *decimal.Decimal) *Level {
+	below, above := s.FindAdjacentBelow(price), s.FindAdjacentAbove(price)
+	switch {
+	case below == nil:
+		return above
+	case above == nil:
+		return below
+	case price.Sub(below.Price).Cmp(above.Price.Sub(price)) < 0:
+		return below
+	default:
+		return above
 	}
 }
 
 // FindAdjacentBelow finds the nearest price level from below the given price.
 func (s *Side) FindAdjacentBelow(price *decimal.Decimal) *Level {
-	if s.Low == nil || price.Cmp(s.Low.Price) <= 0 {
-		return nil
-	}
-	nearest := s.Low
-	for {
-		next := nearest.Higher
-		if next == nil {
-			break
-		}
-		nearestDiff := nearest.Price.Sub(price).Abs()
-		nextDiff := next.Price.Sub(price).Abs()
-		if nearestDiff.Cmp(nextDiff) < 0 || next.Price.Cmp(price) >= 0 {
-			break
-		}
-		nearest = next
+	if level := s.search(positionOf(price), nil); level != nil {
+		return level.Lower
 	}
-	return nearest
+	return s.High
 }
 
 // FindAdjacentAbove finds the nearest price level above the given price.
 func (s *Side) FindAdjacentAbove(price *decimal.Decimal) *Level {
-	if s.High == nil || price.Cmp(s.High.Price) >= 0 {
-		return nil
-	}
-	nearest := s.High
-	for {
-		next := nearest.Lower
-		if next == nil {
-			break
-		}
-		nearestDiff := nearest.Price.Sub(price).Abs()
-		nextDiff := next.Price.Sub(price).Abs()
-		if nearestDiff.Cmp(nextDiff) < 0 || next.Price.Cmp(price) <= 0 {
-			break
-		}
-		nearest = next
+	p := positionOf(price)
+	level := s.search(p, nil)
+	if level != nil && level.compare(p) == 0 {
+		return level.Higher
 	}
-	return nearest
+	return level
+}
+
+// clear removes all price levels.
+func (s *Side) clear() {
+	s.High, s.Low, s.Last = nil, nil, nil
+	s.head = [maxHeight]*Level{}
+	s.height = 0
+	s.length = 0
 }
 
-func (s *Side) insert(opts *UpdateOptions) {
+// randomHeight returns the number of layers of a new level, with each layer being a quarter as likely as the previous.
+func randomHeight() int {
+	return min(bits.TrailingZeros64(rand.Uint64())/2+1, maxHeight)
+}
+
+func (s *Side) insert(p position, opts *UpdateOptions) {
 	level := NewLevel()
 	level.update(opts)
-	nearest := s.FindAdjacent(level.Price)
-	if nearest == nil || s.High == nil || level.Price.Cmp(s.High.Price) > 0 {
-		s.High = level
+	level.key = p.key
+	level.exact = p.exact
+	var predecessors [maxHeight]*Level
+	s.search(p, &predecessors)
+	height := randomHeight()
+	for layer := s.height; layer < height; layer++ {
+		predecessors[layer] = nil
+	}
+	s.height = max(s.height, height)
+	level.next = make([]*Level, height)
+	for layer := range height {
+		level.next[layer] = s.next(predecessors[layer], layer)
+		if predecessors[layer] == nil {
+			s.head[layer] = level
+		} else {
+			predecessors[layer].next[layer] = level
+		}
 	}
-	if nearest == nil || s.Low == nil || level.Price.Cmp(s.Low.Price) < 0 {
+	level.Lower = predecessors[0]
+	level.Higher = level.next[0]
+	if level.Lower == nil {
 		s.Low = level
+	} else {
+		level.Lower.Higher = level
 	}
-	if nearest != nil {
-		if level.Price.Cmp(nearest.Price) > 0 {
-			level.Lower = nearest
-			level.Higher = nearest.Higher
-			nearest.Higher = level
-			if level.Higher != nil {
-				level.Higher.Lower = level
-			}
-		} else if level.Price.Cmp(nearest.Price) < 0 {
-			level.Higher = nearest
-			level.Lower = nearest.Lower
-			nearest.Lower = level
-			if level.Lower != nil {
-				level.Lower.Higher = level
-			}
-		}
+	if level.Higher == nil {
+		s.High = level
+	} else {
+		level.Higher.Lower = level
 	}
-	s.Levels[level.Price.String()] = level
+	s.length++
 }
 
 // Update interprets a [UpdateOptions] message and decides if it should insert, update, or delete the price level.
 func (s *Side) update(opts *UpdateOptions) {
-	level, ok := s.Levels[opts.Price.String()]
-	if !ok && opts.Quantity.Sign() == 1 {
-		s.insert(opts)
-	} else {
-		level.update(opts)
+	p := positionOf(opts.Price)
+	level := s.search(p, nil)
+	if level == nil || level.compare(p) != 0 {
+		if opts.Quantity.Sign() == 1 {
+			s.insert(p, opts)
+		}
+		return
 	}
-	if level != nil && level.Quantity.Sign() <= 0 {
+	level.update(opts)
+	if level.Quantity.Sign() <= 0 {
 		s.delete(level)
 	}
 }
 
 func (s *Side) delete(level *Level) {
+	var predecessors [maxHeight]*Level
+	s.search(position{key: level.key, exact: level.exact, price: level.Price}, &predecessors)
+	for layer := range level.next {
+		if s.next(predecessors[layer], layer) != level {
+			continue
+		}
+		if predecessors[layer] == nil {
+			s.head[layer] = level.next[layer]
+		} else {
+			predecessors[layer].next[layer] = level.next[layer]
+		}
+	}
+	for s.height > 0 && s.head[s.height-1] == nil {
+		s.height--
+	}
 	if s.High == level {
 		s.High = level.Lower
 	}
@@ -138,5 +240,5 @@ func (s *Side) delete(level *Level) {
 	if level.Higher != nil {
 		level.Higher.Lower = level.Lower
 	}
-	delete(s.Levels, level.Price.String())
+	s.length--
 }
@@ -0,0 +1,245 @@
+package book
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+func TestSideInexactPrices(t *testing.T) {
+	side := NewSide()
+	var prices []*decimal.Decimal
+	for _, literal := range []string{"0.00000000002", "0.00000000001", "1", "1000000000001", "1000000000000"} {
+		price, _ := decimal.NewFromString(literal)
+		prices = append(prices, price)
+		side.update(&UpdateOptions{Price: price, Quantity: decimal.NewFromInt64(1)})
+	}
+	var ascending []string
+	for level := side.Low; level != nil; level = level.Higher {
+		ascending = append(ascending, level.Price.String())
+	}
+	expected := []string{"0.00000000001", "0.00000000002", "1", "1000000000000", "1000000000001"}
+	if !slices.Equal(ascending, expected) || side.Len() != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, ascending)
+	}
+	for _, price := range prices {
+		if level := side.Get(price); level == nil || level.Price.Cmp(price) != 0 {
+			t.Errorf("expected a level at %s, got %v", price, level)
+		}
+	}
+	side.update(&UpdateOptions{Price: prices[4], Quantity: decimal.NewFromInt64(0)})
+	if side.High.Price.Cmp(prices[3]) != 0 || side.Len() != 4 {
+		t.Errorf("expected %s to be removed, got %s", prices[4], side.High.Price)
+	}
+}
+
+func TestSide(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	side := NewSide()
+	expected := map[int64]bool{}
+	for range 5000 {
+		price := int64(r.IntN(500))
+		quantity := int64(r.IntN(3))
+		side.update(&UpdateOptions{
+			Price:    decimal.NewFromInt64(price),
+			Quantity: decimal.NewFromInt64(quantity),
+		})
+		if quantity > 0 {
+			expected[price] = true
+		} else {
+			delete(expected, price)
+		}
+	}
+	var ascending, descending []int64
+	for level := side.Low; level != nil; level = level.Higher {
+		ascending = append(ascending, level.Price.Int64())
+	}
+	for level := side.High; level != nil; level = level.Lower {
+		descending = append(descending, level.Price.Int64())
+	}
+	prices := make([]int64, 0, len(expected))
+	for price := range expected {
+		prices = append(prices, price)
+	}
+	slices.Sort(prices)
+	if !slices.Equal(ascending, prices) || side.Len() != len(prices) {
+		t.Fatalf("expected %d ascending levels, got %d of %d", len(prices), len(ascending), side.Len())
+	}
+	slices.Reverse(descending)
+	if !slices.Equal(descending, prices) {
+		t.Fatalf("expected descending levels to match")
+	}
+	middle := prices[len(prices)/2]
+	if side.Get(decimal.NewFromInt64(middle)) == nil {
+		t.Errorf("expected level at %d", middle)
+	}
+	price, _ := decimal.NewFromString(fmt.Sprintf("%d.5", middle))
+	if below := side.FindAdjacentBelow(price); below == nil || below.Price.Int64() != middle {
+		t.Errorf("expected %d below %s, got %v", middle, price, below)
+	}
+	if above := side.FindAdjacentAbove(decimal.NewFromInt64(middle)); above == nil || above.Price.Int64() != prices[len(prices)/2+1] {
+		t.Errorf("expected %d above %d, got %v", prices[len(prices)/2+1], middle, above)
+	}
+}
+
+// benchmarkUpdates returns random updates across the given number of price levels with a tick of 0.5.
+func benchmarkUpdates(levels int) []*UpdateOptions {
+	r := rand.New(rand.NewPCG(1, 2))
+	updates := make([]*UpdateOptions, 4096)
+	for i := range updates {
+		price, _ := decimal.NewFromString(fmt.Sprintf("%d.%d", 50000+r.IntN(levels/2), 5*r.IntN(2)))
+		updates[i] = &UpdateOptions{
+			Direction: Bid,
+			Price:     price,
+			Quantity:  decimal.NewFromInt64(int64(r.IntN(4))),
+			Timestamp: time.Unix(int64(i), 0),
+			Silent:    true,
+		}
+	}
+	return updates
+}
+
+// linearSide is the previous implementation of [Side], which keys levels by their price string and walks the levels linearly to insert them.
+// It is kept as the baseline of the benchmarks.
+type linearSide struct {
+	high   *Level
+	low    *Level
+	levels map[string]*Level
+}
+
+func (s *linearSide) findAdjacent(price *decimal.Decimal) *Level {
+	if s.high == nil || s.low == nil {
+		return nil
+	}
+	if price.Cmp(s.high.Price) > 0 {
+		return s.high
+	}
+	if price.Cmp(s.low.Price) < 0 {
+		return s.low
+	}
+	if s.high.Price.Sub(price).Cmp(price.Sub(s.low.Price)) == 1 {
+		return s.findAdjacentBelow(price)
+	}
+	return s.findAdjacentAbove(price)
+}
+
+func (s *linearSide) findAdjacentBelow(price *decimal.Decimal) *Level {
+	if s.low == nil || price.Cmp(s.low.Price) <= 0 {
+		return nil
+	}
+	nearest := s.low
+	for next := nearest.Higher; next != nil; next = nearest.Higher {
+		if nearest.Price.Sub(price).Abs().Cmp(next.Price.Sub(price).Abs()) < 0 || next.Price.Cmp(price) >= 0 {
+			break
+		}
+		nearest = next
+	}
+	return nearest
+}
+
+func (s *linearSide) findAdjacentAbove(price *decimal.Decimal) *Level {
+	if s.high == nil || price.Cmp(s.high.Price) >= 0 {
+		return nil
+	}
+	nearest := s.high
+	for next := nearest.Lower; next != nil; next = nearest.Lower {
+		if nearest.Price.Sub(price).Abs().Cmp(next.Price.Sub(price).Abs()) < 0 || next.Price.Cmp(price) <= 0 {
+			break
+		}
+		nearest = next
+	}
+	return nearest
+}
+
+func (s *linearSide) insert(opts *UpdateOptions) {
+	level := NewLevel()
+	level.update(opts)
+	nearest := s.findAdjacent(level.Price)
+	if nearest == nil || level.Price.Cmp(s.high.Price) > 0 {
+		s.high = level
+	}
+	if nearest == nil || level.Price.Cmp(s.low.Price) < 0 {
+		s.low = level
+	}
+	if nearest != nil {
+		if level.Price.Cmp(nearest.Price) > 0 {
+			level.Lower, level.Higher = nearest, nearest.Higher
+			nearest.Higher = level
+			if level.Higher != nil {
+				level.Higher.Lower = level
+			}
+		} else if level.Price.Cmp(nearest.Price) < 0 {
+			level.Higher, level.Lower = nearest, nearest.Lower
+			nearest.Lower = level
+			if level.Lower != nil {
+				level.Lower.Higher = level
+			}
+		}
+	}
+	s.levels[level.Price.String()] = level
+}
+
+func (s *linearSide) update(opts *UpdateOptions) {
+	level, ok := s.levels[opts.Price.String()]
+	if !ok {
+		if opts.Quantity.Sign() == 1 {
+			s.insert(opts)
+		}
+		return
+	}
+	level.update(opts)
+	if level.Quantity.Sign() > 0 {
+		return
+	}
+	if s.high == level {
+		s.high = level.Lower
+	}
+	if s.low == level {
+		s.low = level.Higher
+	}
+	if level.Lower != nil {
+		level.Lower.Higher = level.Higher
+	}
+	if level.Higher != nil {
+		level.Higher.Lower = level.Lower
+	}
+	delete(s.levels, level.Price.String())
+}
+
+func benchmarkSide(b *testing.B, levels int, update func(*UpdateOptions)) {
+	for i := range levels {
+		price, _ := decimal.NewFromString(fmt.Sprintf("%d.%d", 50000+i/2, 5*(i%2)))
+		update(&UpdateOptions{
+			Direction: Bid,
+			Price:     price,
+			Quantity:  decimal.NewFromInt64(1),
+			Silent:    true,
+		})
+	}
+	updates := benchmarkUpdates(levels)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		update(updates[i%len(updates)])
+	}
+}
+
+func BenchmarkSideUpdate1000(b *testing.B) {
+	benchmarkSide(b, 1000, NewSide().update)
+}
+
+func BenchmarkSideUpdate10000(b *testing.B) {
+	benchmarkSide(b, 10000, NewSide().update)
+}
+
+func BenchmarkLinearSideUpdate1000(b *testing.B) {
+	benchmarkSide(b, 1000, (&linearSide{levels: make(map[string]*Level)}).update)
+}
+
+func BenchmarkLinearSideUpdate10000(b *testing.B) {
+	benchmarkSide(b, 10000, (&linearSide{levels: make(map[string]*Level)}).update)
+}
@@ -0,0 +1,102 @@
+package book
+
+import (
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// Snapshot is an immutable copy of the best price levels of a [Book].
+type Snapshot struct {
+	Name string `json:"name,omitempty"`
+
+	// Bids from the highest price and asks from the lowest price.
+	Bids []*LevelSnapshot `json:"bids,omitempty"`
+	Asks []*LevelSnapshot `json:"asks,omitempty"`
+
+	// Time of the latest update applied to the book.
+	Timestamp time.Time `json:"timestamp,omitempty"`
+
+	// Time at which the snapshot was taken.
+	Created time.Time `json:"created,omitempty"`
+}
+
+// LevelSnapshot is a copy of a [Level] with its orders in time priority.
+type LevelSnapshot struct {
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	Quantity  *decimal.Decimal `json:"quantity,omitempty"`
+	Timestamp time.Time        `json:"time,omitempty"`
+	Orders    []*Order         `json:"orders,omitempty"`
+}
+
+// Snapshot copies up to depth price levels of each side, or all of them if depth is not positive.
+// The book is only locked while copying, so the snapshot can be held and read without blocking updates.
+func (b *Book) Snapshot(depth int) *Snapshot {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return &Snapshot{
+		Name:      b.Name,
+		Bids:      snapshotSide(b.Bids, depth),
+		Asks:      snapshotSide(b.Asks, depth),
+		Timestamp: b.updated,
+		Created:   time.Now(),
+	}
+}
+
+// snapshotSide copies up to depth levels of a side from the best price.
+func snapshotSide(side *Side, depth int) []*LevelSnapshot {
+	var levels []*LevelSnapshot
+	for cursor := range side.levels(depth) {
+		level := &LevelSnapshot{
+			Price:     cursor.Price.Copy(),
+			Quantity:  cursor.Quantity.Copy(),
+			Timestamp: cursor.Timestamp,
+		}
+		for _, order := range cursor.Queue() {
+			level.Orders = append(level.Orders, &Order{
+				ID:         order.ID,
+				LimitPrice: order.LimitPrice.Copy(),
+				Quantity:   order.Quantity.Copy(),
+				Timestamp:  order.Timestamp,
+			})
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+// BestBid returns the highest bid price level or nil if there are no bids.
+func (s *Snapshot) BestBid() *LevelSnapshot {
+	if len(s.Bids) == 0 {
+		return nil
+	}
+	return s.Bids[0]
+}
+
+// BestAsk returns the lowest ask price level or nil if there are no asks.
+func (s *Snapshot) BestAsk() *LevelSnapshot {
+	if len(s.Asks) == 0 {
+		return nil
+	}
+	return s.Asks[0]
+}
+
+// Midpoint returns the midpoint of the snapshot.
+func (s *Snapshot) Midpoint() *decimal.Decimal {
+	return midpoint(s.bestPrices())
+}
+
+// Spread returns the relative difference between the bid-ask price of the snapshot.
+func (s *Snapshot) Spread() *decimal.Decimal {
+	return spread(s.bestPrices())
+}
+
+func (s *Snapshot) bestPrices() (bid *decimal.Decimal, ask *decimal.Decimal) {
+	if best := s.BestBid(); best != nil {
+		bid = best.Price
+	}
+	if best := s.BestAsk(); best != nil {
+		ask = best.Price
+	}
+	return bid, ask
+}
@@ -1,120 +1,282 @@
 package callback
 
 import (
-	"maps"
+	"cmp"
+	"fmt"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
+// PanicError is passed to the error handler of a [Manager] when a callback panics.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("callback panicked: %v\n%s", e.Value, e.Stack)
+}
+
 // Manager manages the lifecycle of a collection of generic [Callback] structs.
+//
+// Callbacks are called in descending order of [Callback.Priority] and in registration order within the same priority.
+// A panic in a callback propagates to the caller unless ErrorHandler is set.
 type Manager[T any] struct {
-	callbacks map[*Callback[T]]bool
+	// Receives a [PanicError] when a callback panics, so the remaining callbacks are still called.
+	// Panics are not recovered if nil.
+	ErrorHandler func(error)
+
+	callbacks []*Callback[T]
+	seq       uint64
 	mux       sync.RWMutex
 }
 
 // NewManager constructs a new [Manager] structs.
 func NewManager[T any]() *Manager[T] {
-	return &Manager[T]{
-		callbacks: make(map[*Callback[T]]bool),
-	}
+	return &Manager[T]{}
 }
 
-// Register adds a [Callback] struct to the map.
+// Register adds a [Callback] struct to the manager.
+// The priority of a callback must not be changed after it is registered.
 func (m *Manager[T]) Register(c *Callback[T]) *Callback[T] {
+	c.init()
 	m.mux.Lock()
 	defer m.mux.Unlock()
-	m.callbacks[c] = true
+	if slices.Contains(m.callbacks, c) {
+		return c
+	}
+	m.seq++
+	c.seq = m.seq
+	i, _ := slices.BinarySearchFunc(m.callbacks, c, compareCallbacks[T])
+	m.callbacks = slices.Insert(m.callbacks, i, c)
 	return c
 }
 
-// Reregister removes a [Callback] struct from the map.
+// compareCallbacks orders callbacks by descending priority and ascending registration.
+func compareCallbacks[T any](a, b *Callback[T]) int {
+	if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// Deregister removes a [Callback] struct from the manager and disables it.
 func (m *Manager[T]) Deregister(c *Callback[T]) *Callback[T] {
+	c.Disable()
 	m.mux.Lock()
 	defer m.mux.Unlock()
-	delete(m.callbacks, c)
+	m.callbacks = slices.DeleteFunc(m.callbacks, func(callback *Callback[T]) bool {
+		return callback == c
+	})
 	return c
 }
 
-// Reset clears all [Callback] struct from the map.
+// Reset disables and removes all [Callback] structs from the manager.
 func (m *Manager[T]) Reset() {
 	m.mux.Lock()
-	defer m.mux.Unlock()
-	m.callbacks = make(map[*Callback[T]]bool)
+	callbacks := m.callbacks
+	m.callbacks = nil
+	m.mux.Unlock()
+	for _, callback := range callbacks {
+		callback.Disable()
+	}
 }
 
 type Action[T any] func(*Event[T])
 
-// Recurring adds a recurring [Callback] struct to the map.
+// Recurring adds a recurring [Callback] struct to the manager.
 func (m *Manager[T]) Recurring(action Action[T]) *Callback[T] {
 	return m.Register(&Callback[T]{
-		Action:  action,
-		Enabled: true,
+		Action: action,
 	})
 }
 
-// Once adds a [Callback] to the map that is deregistered after first execution.
+// Priority adds a recurring [Callback] struct that is called before the callbacks of a lower priority.
+func (m *Manager[T]) Priority(priority int, action Action[T]) *Callback[T] {
+	return m.Register(&Callback[T]{
+		Action:   action,
+		Priority: priority,
+	})
+}
+
+// Once adds a [Callback] to the manager that is disabled after first execution.
 func (m *Manager[T]) Once(action Action[T]) *Callback[T] {
-	callback := &Callback[T]{Enabled: true}
+	callback := &Callback[T]{}
 	callback.Action = func(e *Event[T]) {
-		action(e)
-		callback.Enabled = false
+		if callback.disabled.CompareAndSwap(false, true) {
+			defer callback.Disable()
+			action(e)
+		}
 	}
 	return m.Register(callback)
 }
 
-// SleepUntilDisabled adds a [Callback] struct to the map and pauses the current goroutine until the callback is disabled.
+// Async adds a recurring [Callback] that runs on its own goroutine, so a slow action does not block [Manager.Call].
+// Events are queued up to queueSize and handled according to the policy, which defaults to [OverflowDropOldest].
+// The goroutine exits when the callback is disabled.
+func (m *Manager[T]) Async(action Action[T], queueSize int, policy ...OverflowPolicy) *Callback[T] {
+	callback := &Callback[T]{Action: action, detached: true}
+	callback.init()
+	callback.queue = newSubscription[*Event[T]](queueSize, callback.done, policy...)
+	go func() {
+		<-callback.done
+		callback.queue.close()
+	}()
+	go func() {
+		for e := range callback.queue.ch {
+			if callback.active() {
+				m.invoke(func() {
+					action(e)
+				})
+			}
+		}
+	}()
+	return m.Register(callback)
+}
+
+// SleepUntilDisabled adds a [Callback] struct to the manager and pauses the current goroutine until the callback is disabled.
+// The action runs on a new goroutine for each event.
 func (m *Manager[T]) SleepUntilDisabled(action Action[T]) *Callback[T] {
-	callback := &Callback[T]{Enabled: true}
-	var wg sync.WaitGroup
-	wg.Add(1)
+	callback := &Callback[T]{detached: true}
 	callback.Action = func(e *Event[T]) {
-		go func() {
+		go m.invoke(func() {
 			action(e)
 			if !e.Callback.Enabled {
-				wg.Done()
+				e.Callback.Disable()
 			}
-		}()
+		})
 	}
 	m.Register(callback)
-	wg.Wait()
+	<-callback.Done()
 	return callback
 }
 
-// Map returns a clone of the internal [Callback] map.
+// Map returns the registered callbacks as a map.
 func (m *Manager[T]) Map() map[*Callback[T]]bool {
 	m.mux.RLock()
 	defer m.mux.RUnlock()
-	return maps.Clone(m.callbacks)
+	callbacks := make(map[*Callback[T]]bool, len(m.callbacks))
+	for _, callback := range m.callbacks {
+		callbacks[callback] = true
+	}
+	return callbacks
 }
 
-// Call fans out [Event] objects across all callbacks in the map.
+// Len returns the number of registered callbacks.
+func (m *Manager[T]) Len() int {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return len(m.callbacks)
+}
+
+// Call fans out [Event] objects across all enabled callbacks in order and removes the disabled ones.
 func (m *Manager[T]) Call(v T) {
-	callbacks := m.Map()
-	for callback := range callbacks {
-		if callback.Enabled {
-			callback.Call(v)
+	m.mux.RLock()
+	callbacks := slices.Clone(m.callbacks)
+	m.mux.RUnlock()
+	disabled := false
+	for _, callback := range callbacks {
+		if callback.active() {
+			m.invoke(func() {
+				callback.Call(v)
+			})
 		}
-	}
-	m.mux.Lock()
-	for callback := range callbacks {
-		if !callback.Enabled {
-			delete(m.callbacks, callback)
+		if !callback.detached && !callback.Enabled {
+			callback.Disable()
 		}
+		disabled = disabled || !callback.active()
 	}
+	if !disabled {
+		return
+	}
+	m.mux.Lock()
+	m.callbacks = slices.DeleteFunc(m.callbacks, func(callback *Callback[T]) bool {
+		return !callback.active()
+	})
 	m.mux.Unlock()
 }
 
+// invoke runs f and passes a recovered panic to the error handler if there is one.
+func (m *Manager[T]) invoke(f func()) {
+	handler := m.ErrorHandler
+	if handler == nil {
+		f()
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			handler(&PanicError{Value: r, Stack: debug.Stack()})
+		}
+	}()
+	f()
+}
+
 // Callback contains the function reference and parameters.
+//
+// A callback is enabled when it is registered. Once disabled, it is removed from its manager and cannot be enabled again.
 type Callback[T any] struct {
-	Action  Action[T]
+	Action Action[T]
+
+	// Setting Enabled to false within the action disables the callback once the action returns.
+	// It is not synchronized, so use [Callback.Disable] from other goroutines and within the actions of [Manager.Async], and [Callback.Done] to observe it.
 	Enabled bool
+
+	// Callbacks with a higher priority are called first.
+	Priority int
+
+	disabled atomic.Bool
+	// Whether the action runs on another goroutine, which checks Enabled once it returns.
+	detached    bool
+	seq         uint64
+	done        chan struct{}
+	queue       *subscription[*Event[T]]
+	initOnce    sync.Once
+	disableOnce sync.Once
+}
+
+func (c *Callback[T]) init() {
+	c.initOnce.Do(func() {
+		c.done = make(chan struct{})
+		c.Enabled = true
+	})
+}
+
+// active returns whether the callback was not disabled with [Callback.Disable].
+func (c *Callback[T]) active() bool {
+	return !c.disabled.Load()
+}
+
+// Disable stops the callback from being called. It is safe to call from any goroutine, including within the action.
+func (c *Callback[T]) Disable() {
+	c.init()
+	c.disableOnce.Do(func() {
+		c.disabled.Store(true)
+		close(c.done)
+	})
+}
+
+// Done returns a channel that is closed once the callback is disabled.
+func (c *Callback[T]) Done() <-chan struct{} {
+	c.init()
+	return c.done
 }
 
 // Call constructs an [Event] object and passes them to the internal Action function.
+// The event is queued instead if the callback was added with [Manager.Async].
 func (c *Callback[T]) Call(v T) {
-	c.Action(&Event[T]{
+	e := &Event[T]{
 		Data:     v,
 		Callback: c,
-	})
+	}
+	if c.queue != nil {
+		if !c.queue.send(e) {
+			c.Disable()
+		}
+		return
+	}
+	c.Action(e)
 }
 
 // Event contains the content and reference to the [Callback] struct.
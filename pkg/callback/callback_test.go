@@ -0,0 +1,139 @@
+package callback
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManagerOrder(t *testing.T) {
+	m := NewManager[int]()
+	var order []string
+	m.Recurring(func(e *Event[int]) {
+		order = append(order, "first")
+	})
+	m.Priority(10, func(e *Event[int]) {
+		order = append(order, "priority")
+	})
+	m.Recurring(func(e *Event[int]) {
+		panic("failed")
+	})
+	m.Recurring(func(e *Event[int]) {
+		order = append(order, "last")
+	})
+	var panics []error
+	m.ErrorHandler = func(err error) {
+		panics = append(panics, err)
+	}
+	m.Call(1)
+	if expected := []string{"priority", "first", "last"}; !slices.Equal(order, expected) {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+	var panicErr *PanicError
+	if len(panics) != 1 || !errors.As(panics[0], &panicErr) || panicErr.Value != "failed" {
+		t.Errorf("expected a recovered panic, got %v", panics)
+	}
+}
+
+func TestManagerOnce(t *testing.T) {
+	m := NewManager[int]()
+	var calls atomic.Int32
+	m.Once(func(e *Event[int]) {
+		calls.Add(1)
+	})
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Call(i)
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected 1 call, got %d", n)
+	}
+	if n := m.Len(); n != 0 {
+		t.Errorf("expected the callback to be removed, got %d", n)
+	}
+}
+
+func TestManagerAsync(t *testing.T) {
+	m := NewManager[int]()
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	received := make(chan int, 10)
+	callback := m.Async(func(e *Event[int]) {
+		started <- struct{}{}
+		<-release
+		received <- e.Data
+	}, 2, OverflowDropNewest)
+	m.Call(1)
+	<-started
+	done := make(chan struct{})
+	go func() {
+		for i := 2; i <= 5; i++ {
+			m.Call(i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Call blocked on a slow async callback")
+	}
+	close(release)
+	var values []int
+	for len(values) < 3 {
+		select {
+		case v := <-received:
+			values = append(values, v)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for async callback, got %v", values)
+		}
+	}
+	if !slices.Equal(values, []int{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v", values)
+	}
+	m.Deregister(callback)
+	select {
+	case <-callback.Done():
+	default:
+		t.Errorf("expected the callback to be disabled")
+	}
+}
+
+func TestManagerPanic(t *testing.T) {
+	m := NewManager[int]()
+	m.Recurring(func(e *Event[int]) {
+		panic("failed")
+	})
+	defer func() {
+		if r := recover(); r != "failed" {
+			t.Errorf("expected the panic to propagate without an error handler, got %v", r)
+		}
+	}()
+	m.Call(1)
+}
+
+func TestCallbackEnabled(t *testing.T) {
+	m := NewManager[int]()
+	var calls int
+	callback := m.Recurring(func(e *Event[int]) {
+		calls++
+		e.Callback.Enabled = false
+	})
+	m.Call(1)
+	m.Call(2)
+	if calls != 1 || m.Len() != 0 {
+		t.Errorf("expected 1 call and the callback to be removed, got %d calls and %d callbacks", calls, m.Len())
+	}
+	select {
+	case <-callback.Done():
+	default:
+		t.Errorf("expected the callback to be disabled")
+	}
+}
@@ -0,0 +1,130 @@
+package callback
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// DefaultBufferSize is the channel capacity used by [Manager.All].
+const DefaultBufferSize = 256
+
+// OverflowPolicy determines what happens when a value is delivered to a full subscription channel.
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest discards the oldest buffered value to make room for the new one.
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowDropNewest discards the new value.
+	OverflowDropNewest
+	// OverflowBlock waits until the consumer receives, which also blocks [Manager.Call].
+	OverflowBlock
+	// OverflowDisconnect closes the channel and removes the subscription.
+	OverflowDisconnect
+)
+
+// subscription delivers values to a buffered channel according to an [OverflowPolicy].
+type subscription[T any] struct {
+	ch     chan T
+	done   chan struct{}
+	stop   <-chan struct{}
+	policy OverflowPolicy
+	closed bool
+	mux    sync.Mutex
+}
+
+// newSubscription constructs a subscription whose [OverflowBlock] sends are abandoned once stop is closed.
+func newSubscription[T any](bufferSize int, stop <-chan struct{}, policy ...OverflowPolicy) *subscription[T] {
+	s := &subscription[T]{
+		ch:   make(chan T, max(bufferSize, 0)),
+		done: make(chan struct{}),
+		stop: stop,
+	}
+	if len(policy) > 0 {
+		s.policy = policy[0]
+	}
+	return s
+}
+
+// send delivers a value according to the overflow policy and returns false if the subscription is closed.
+func (s *subscription[T]) send(v T) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return false
+	}
+	select {
+	case s.ch <- v:
+		return true
+	default:
+	}
+	switch s.policy {
+	case OverflowDropOldest:
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- v:
+		default:
+		}
+	case OverflowBlock:
+		select {
+		case s.ch <- v:
+		case <-s.stop:
+		}
+	case OverflowDisconnect:
+		s.closeLocked()
+		return false
+	}
+	return true
+}
+
+func (s *subscription[T]) close() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.closeLocked()
+}
+
+func (s *subscription[T]) closeLocked() {
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+		close(s.done)
+	}
+}
+
+// Subscribe returns a channel receiving the values passed to [Manager.Call] until ctx is done or the consumer falls behind under [OverflowDisconnect].
+// The channel holds up to bufferSize values and is closed when the subscription ends.
+// The policy defaults to [OverflowDropOldest], so a slow consumer cannot stall the caller.
+func (m *Manager[T]) Subscribe(ctx context.Context, bufferSize int, policy ...OverflowPolicy) <-chan T {
+	s := newSubscription[T](bufferSize, ctx.Done(), policy...)
+	callback := m.Recurring(func(e *Event[T]) {
+		if !s.send(e.Data) {
+			e.Callback.Disable()
+		}
+	})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.done:
+		}
+		m.Deregister(callback)
+		s.close()
+	}()
+	return s.ch
+}
+
+// All returns an iterator over the values passed to [Manager.Call] until ctx is done or the loop exits.
+// Values are buffered up to [DefaultBufferSize] and the oldest are dropped if the loop falls behind.
+func (m *Manager[T]) All(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		for v := range m.Subscribe(ctx, DefaultBufferSize) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package callback
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		policy   OverflowPolicy
+		expected []int
+	}{
+		{OverflowDropOldest, []int{3, 4}},
+		{OverflowDropNewest, []int{1, 2}},
+		{OverflowDisconnect, []int{1, 2}},
+	}
+	for _, test := range tests {
+		m := NewManager[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := m.Subscribe(ctx, 2, test.policy)
+		for i := 1; i <= 4; i++ {
+			m.Call(i)
+		}
+		cancel()
+		var received []int
+		for v := range ch {
+			received = append(received, v)
+		}
+		if !slices.Equal(received, test.expected) {
+			t.Errorf("policy %d: expected %v, got %v", test.policy, test.expected, received)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	m := NewManager[int]()
+	ready := make(chan struct{})
+	done := make(chan []int)
+	go func() {
+		var received []int
+		close(ready)
+		for v := range m.All(context.Background()) {
+			received = append(received, v)
+			if len(received) == 3 {
+				break
+			}
+		}
+		done <- received
+	}()
+	<-ready
+	for len(m.Map()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 1; i <= 3; i++ {
+		m.Call(i)
+	}
+	if received := <-done; !slices.Equal(received, []int{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v", received)
+	}
+	for len(m.Map()) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -86,6 +86,16 @@ func NewFromInt64(i int64) *Decimal {
 	return d.SetScale(DefaultScale)
 }
 
+// NewFromFixedPoint creates a new [Decimal] object from an integer v representing v / 10^scale.
+func NewFromFixedPoint(v int64, scale int64) *Decimal {
+	d := new(Decimal)
+	d.increment = DefaultIncrement
+	d.rounding = BankersRound
+	d.integer = new(big.Int).SetInt64(v)
+	d.scale = scale
+	return d
+}
+
 // NewFromBigFloat creates a new [Decimal] object from a [big.Float].
 func NewFromBigFloat(f *big.Float) *Decimal {
 	var numDecimals int
@@ -170,6 +180,31 @@ func (d *Decimal) Int64() int64 {
 		Int64()
 }
 
+// FixedPoint returns d multiplied by 10^scale as an int64 without allocating.
+// The result is only valid if ok is true, which requires it to be exact and within the range of int64.
+func (d *Decimal) FixedPoint(scale int64) (v int64, ok bool) {
+	if d.integer == nil || d.Sign() == 0 {
+		return 0, true
+	}
+	if !d.integer.IsInt64() {
+		return 0, false
+	}
+	v = d.integer.Int64()
+	for i := d.scale; i < scale; i++ {
+		if v > math.MaxInt64/10 || v < math.MinInt64/10 {
+			return 0, false
+		}
+		v *= 10
+	}
+	for i := d.scale; i > scale; i-- {
+		if v%10 != 0 {
+			return 0, false
+		}
+		v /= 10
+	}
+	return v, true
+}
+
 // String returns the literal representation of m.
 func (d *Decimal) String() string {
 	return d.Rat().FloatString(int(d.scale))
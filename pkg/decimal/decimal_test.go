@@ -48,3 +48,28 @@ func TestRounding(t *testing.T) {
 		t.Errorf("SetScale(2) != 1.00, got %s", d)
 	}
 }
+
+func TestFixedPoint(t *testing.T) {
+	tests := []struct {
+		input string
+		scale int64
+		v     int64
+		ok    bool
+	}{
+		{"1.015", 4, 10150, true},
+		{"1.0150", 3, 1015, true},
+		{"1.015", 2, 0, false},
+		{"92233720368.54775807", 10, 0, false},
+	}
+	for _, test := range tests {
+		d, err := NewFromString(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, ok := d.FixedPoint(test.scale); v != test.v || ok != test.ok {
+			t.Errorf("FixedPoint(%s, %d) != (%d, %t), got (%d, %t)", test.input, test.scale, test.v, test.ok, v, ok)
+		} else if ok && NewFromFixedPoint(v, test.scale).Cmp(d) != 0 {
+			t.Errorf("NewFromFixedPoint(%d, %d) != %s, got %s", v, test.scale, test.input, NewFromFixedPoint(v, test.scale))
+		}
+	}
+}
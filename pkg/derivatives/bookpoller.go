@@ -0,0 +1,142 @@
+package derivatives
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+// Updates converts the price levels to [book.UpdateOptions] for [book.Book.Replace] with the given timestamp.
+func (ob *OrderBook) Updates(timestamp time.Time) []*book.UpdateOptions {
+	var updates []*book.UpdateOptions
+	sides := []struct {
+		direction book.BookDirection
+		levels    []PriceLevel
+	}{{book.Bid, ob.Bids}, {book.Ask, ob.Asks}}
+	for _, side := range sides {
+		for _, level := range side.levels {
+			updates = append(updates, &book.UpdateOptions{
+				Direction: side.direction,
+				Price:     level.Price,
+				Quantity:  level.Volume,
+				Timestamp: timestamp,
+			})
+		}
+	}
+	return updates
+}
+
+// LoadOrderBook replaces the levels of the managed book of a product with a REST [OrderBook], creating the book if needed.
+// The differences with the previous levels are passed to OnUpdated of the book. The current time is used if timestamp is zero.
+//
+// The snapshot has no sequence number, so the next delta received from WebSocket is applied as is.
+func (bm *BookManager) LoadOrderBook(productID string, ob *OrderBook, timestamp time.Time) *book.Book {
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	b := bm.GetBook(productID)
+	if b == nil {
+		b = bm.CreateBook(productID)
+	}
+	b.Replace(ob.Updates(timestamp))
+	bm.seqMux.Lock()
+	if s, ok := bm.sequences[b.Name]; ok {
+		s.status.Recovering = false
+		s.baseline = true
+		clear(s.pending)
+		s.status.Pending = 0
+	}
+	bm.seqMux.Unlock()
+	return b
+}
+
+// BookPoller maintains the books of a [BookManager] by polling the REST order book, which replaces WebSocket subscriptions where they are unavailable.
+// Each poll applies the differences with the previous one, so OnUpdated of the books is called for each changed price level.
+type BookPoller struct {
+	ProductIDs []string
+
+	// Interval between polls.
+	Interval time.Duration
+
+	Manager  *BookManager
+	OnPolled *callback.Manager[*book.Book]
+	OnError  *callback.Manager[error]
+
+	rest *REST
+	stop chan struct{}
+	done chan struct{}
+	mux  sync.Mutex
+}
+
+// NewBookPoller constructs a [BookPoller] polling the given products every second into a new [BookManager].
+func NewBookPoller(rest *REST, productIDs ...string) *BookPoller {
+	return &BookPoller{
+		ProductIDs: productIDs,
+		Interval:   time.Second,
+		Manager:    NewBookManager(),
+		OnPolled:   callback.NewManager[*book.Book](),
+		OnError:    callback.NewManager[error](),
+		rest:       rest,
+	}
+}
+
+// Start polls the books every interval in a new goroutine.
+func (p *BookPoller) Start() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.stop != nil {
+		return fmt.Errorf("book poller already started")
+	}
+	if p.Interval <= 0 {
+		return fmt.Errorf("interval %s must be positive", p.Interval)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	p.stop = stop
+	p.done = done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.Interval)
+		defer ticker.Stop()
+		for {
+			for _, productID := range p.ProductIDs {
+				if err := p.Poll(productID); err != nil {
+					p.OnError.Call(err)
+				}
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Poll retrieves the order book of a product once and loads it into the managed book.
+func (p *BookPoller) Poll(productID string) error {
+	resp, err := p.rest.OrderBook(&OrderBookRequest{
+		Symbol: productID,
+	})
+	if err != nil {
+		return fmt.Errorf("\"%s\" order book: %w", productID, err)
+	}
+	p.OnPolled.Call(p.Manager.LoadOrderBook(productID, &resp.Result.OrderBook, resp.Result.ServerTime))
+	return nil
+}
+
+// Stop stops polling and waits for the current poll to finish.
+func (p *BookPoller) Stop() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	p.stop = nil
+}
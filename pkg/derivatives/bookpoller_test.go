@@ -0,0 +1,53 @@
+package derivatives
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+func TestBookPoller(t *testing.T) {
+	var polls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"result":"success","serverTime":"2024-01-01T00:00:00Z","orderBook":{"asks":[[101.0,1.0]],"bids":[[100.0,1.0],[99.0,2.0]]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":"success","serverTime":"2024-01-01T00:00:01Z","orderBook":{"asks":[[101.0,1.5]],"bids":[[100.0,1.0]]}}`))
+	}))
+	defer server.Close()
+	rest := NewREST()
+	rest.BaseURL = server.URL
+	poller := NewBookPoller(rest, "PF_XBTUSD")
+	var updates []*book.UpdateOptions
+	poller.Manager.OnCreateBook.Recurring(func(e *callback.Event[*book.Book]) {
+		e.Data.OnUpdated.Recurring(func(e *callback.Event[*book.UpdateOptions]) {
+			updates = append(updates, e.Data)
+		})
+	})
+	if err := poller.Poll("PF_XBTUSD"); err != nil {
+		t.Fatalf("Poll: %s", err)
+	}
+	if len(updates) != 3 {
+		t.Errorf("expected 3 updates from the first poll, got %d", len(updates))
+	}
+	updates = nil
+	if err := poller.Poll("PF_XBTUSD"); err != nil {
+		t.Fatalf("Poll: %s", err)
+	}
+	if len(updates) != 2 || updates[0].Price.Int64() != 99 || updates[0].Quantity.Sign() != 0 || updates[1].Price.Int64() != 101 {
+		t.Errorf("expected the removal of 99 and the update of 101, got %d updates", len(updates))
+	}
+	if expected := time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC); len(updates) == 2 && !updates[1].Timestamp.Equal(expected) {
+		t.Errorf("expected the server time %s, got %s", expected, updates[1].Timestamp)
+	}
+	b := poller.Manager.GetBook("PF_XBTUSD")
+	if b.Bids.Len() != 1 || b.BestAsk().Quantity.String() != "1.5" {
+		t.Errorf("expected the book to match the second poll")
+	}
+}
@@ -20,13 +20,38 @@ type BookManager struct {
 	books        map[string]*book.Book
 	mux          sync.RWMutex
 	OnCreateBook *callback.Manager[*book.Book]
+
+	// Number of out-of-order deltas buffered per product before a new snapshot is retrieved.
+	// Sequence numbers are ignored if it is not positive.
+	// Without WebSocket and REST, the buffered deltas are applied past a gap and [ErrSequenceGap] is returned.
+	ReorderWindow int
+
+	// Linked client used to resubscribe a book after a sequence gap.
+	WebSocket *WebSocket
+
+	// Client used to retrieve a snapshot in the background after a sequence gap if WebSocket is nil or resubscribing fails.
+	// The deltas following the snapshot cannot be matched to it, so the next delta received is applied.
+	REST *REST
+
+	// Called when a sequence gap is detected.
+	OnGap *callback.Manager[*GapEvent]
+
+	// Called when a snapshot cannot be retrieved from REST after a sequence gap.
+	OnError *callback.Manager[error]
+
+	sequences map[string]*sequence
+	seqMux    sync.Mutex
 }
 
 // NewBookManager constructs a new [BookManager] struct.
 func NewBookManager() *BookManager {
 	return &BookManager{
-		books:        make(map[string]*book.Book),
-		OnCreateBook: callback.NewManager[*book.Book](),
+		books:         make(map[string]*book.Book),
+		OnCreateBook:  callback.NewManager[*book.Book](),
+		ReorderWindow: DefaultReorderWindow,
+		OnGap:         callback.NewManager[*GapEvent](),
+		OnError:       callback.NewManager[error](),
+		sequences:     make(map[string]*sequence),
 	}
 }
 
@@ -50,9 +75,9 @@ func (bm *BookManager) Update(m *callback.Event[*kraken.WebSocketMessage]) error
 	}
 	switch *channel {
 	case "book_snapshot":
-		return bm.UpdateSnapshot(book, event)
+		return bm.applySnapshot(book, event)
 	case "book":
-		return bm.UpdateDelta(book, event)
+		return bm.applyDelta(book, event)
 	default:
 		return fmt.Errorf("unknown channel: %s", *channel)
 	}
@@ -0,0 +1,251 @@
+package derivatives
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// bookMessage encodes a futures book message as a callback event.
+func bookMessage(t *testing.T, m map[string]any) *callback.Event[*kraken.WebSocketMessage] {
+	m["product_id"] = "PF_XBTUSD"
+	m["timestamp"] = 1700000000000
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &callback.Event[*kraken.WebSocketMessage]{Data: kraken.NewWebSocketMessage(data)}
+}
+
+func bookSnapshot(seq int64) map[string]any {
+	return map[string]any{
+		"feed": "book_snapshot",
+		"seq":  seq,
+		"bids": []any{map[string]any{"price": 100, "qty": 1}},
+		"asks": []any{map[string]any{"price": 101, "qty": 1}},
+	}
+}
+
+func bookDelta(seq int64, price float64, qty float64) map[string]any {
+	return map[string]any{
+		"feed":  "book",
+		"seq":   seq,
+		"side":  "buy",
+		"price": price,
+		"qty":   qty,
+	}
+}
+
+func TestBookManagerSequence(t *testing.T) {
+	bm := NewBookManager()
+	bm.ReorderWindow = 2
+	update := func(m map[string]any) error {
+		return bm.Update(bookMessage(t, m))
+	}
+	if err := update(bookSnapshot(1)); err != nil {
+		t.Fatalf("snapshot: %s", err)
+	}
+	b := bm.GetBook("PF_XBTUSD")
+	if err := update(bookDelta(3, 100, 3)); err != nil {
+		t.Fatalf("delta: %s", err)
+	}
+	if status := bm.Status("PF_XBTUSD"); status.Pending != 1 || b.BestBid().Quantity.Int64() != 1 {
+		t.Errorf("expected the delta to be buffered, got %+v", status)
+	}
+	if err := update(bookDelta(2, 99, 2)); err != nil {
+		t.Fatalf("delta: %s", err)
+	}
+	if err := update(bookDelta(2, 99, 2)); err != nil {
+		t.Fatalf("delta: %s", err)
+	}
+	status := bm.Status("PF_XBTUSD")
+	if status.Sequence != 3 || status.Pending != 0 || status.Stale != 1 || b.BestBid().Quantity.Int64() != 3 || b.Bids.Len() != 2 {
+		t.Errorf("expected the deltas to be applied in order, got %+v", status)
+	}
+	for seq := int64(5); seq <= 6; seq++ {
+		if err := update(bookDelta(seq, 98, 1)); err != nil {
+			t.Fatalf("delta: %s", err)
+		}
+	}
+	if err := update(bookDelta(7, 98, 1)); !errors.Is(err, ErrSequenceGap) {
+		t.Errorf("expected a sequence gap, got %v", err)
+	}
+	if err := update(bookDelta(8, 97, 1)); err != nil {
+		t.Fatalf("delta: %s", err)
+	}
+	if status := bm.Status("PF_XBTUSD"); status.Recovering || status.Gaps != 1 || status.Sequence != 8 || b.Bids.Len() != 4 {
+		t.Errorf("expected the deltas to be applied past the gap, got %+v", status)
+	}
+
+	server := newTestServer(t, func(request map[string]any) []any {
+		if request["event"] != "subscribe" {
+			return nil
+		}
+		snapshot := bookSnapshot(50)
+		snapshot["product_id"] = "PF_XBTUSD"
+		snapshot["timestamp"] = 1700000000000
+		return []any{snapshot}
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	errs := make(chan error, 10)
+	ws.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
+		if err := bm.Update(e); err != nil {
+			errs <- err
+		}
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	bm.WebSocket = ws
+	if err := update(bookSnapshot(10)); err != nil {
+		t.Fatalf("snapshot: %s", err)
+	}
+	if status := bm.Status("PF_XBTUSD"); status.Sequence != 10 || b.Bids.Len() != 1 {
+		t.Errorf("expected the book to be replaced by the snapshot, got %+v", status)
+	}
+	gaps := make(chan *GapEvent, 1)
+	bm.OnGap.Recurring(func(e *callback.Event[*GapEvent]) {
+		gaps <- e.Data
+	})
+	for seq := int64(12); seq <= 14; seq++ {
+		if err := update(bookDelta(seq, 98, 1)); err != nil {
+			t.Fatalf("delta: %s", err)
+		}
+	}
+	select {
+	case gap := <-gaps:
+		if gap.Expected != 11 || gap.Received != 14 || gap.Recovery != "resubscribe" {
+			t.Errorf("expected a gap from 11 recovered by resubscribing, got %+v", gap)
+		}
+	default:
+		t.Fatal("expected a gap event")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for bm.Status("PF_XBTUSD").Sequence != 50 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := bm.Status("PF_XBTUSD"); status.Recovering || status.Recoveries != 1 {
+		t.Errorf("expected the book to be recovered after resubscribing, got %+v", status)
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("Update: %s", err)
+	default:
+	}
+}
+
+func TestBookManagerRESTRecovery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":"success","serverTime":"2024-01-01T00:00:00Z","orderBook":{"bids":[[100,2],[99,1]],"asks":[[101,3]]}}`))
+	}))
+	defer server.Close()
+	bm := NewBookManager()
+	bm.ReorderWindow = 2
+	bm.REST = NewREST()
+	bm.REST.BaseURL = server.URL
+	gaps := make(chan *GapEvent, 1)
+	bm.OnGap.Recurring(func(e *callback.Event[*GapEvent]) {
+		gaps <- e.Data
+	})
+	update := func(m map[string]any) error {
+		return bm.Update(bookMessage(t, m))
+	}
+	if err := update(bookSnapshot(1)); err != nil {
+		t.Fatalf("snapshot: %s", err)
+	}
+	for seq := int64(3); seq <= 5; seq++ {
+		if err := update(bookDelta(seq, 98, 1)); err != nil {
+			t.Fatalf("delta: %s", err)
+		}
+	}
+	select {
+	case gap := <-gaps:
+		if gap.Expected != 2 || gap.Recovery != "rest" {
+			t.Errorf("expected a gap from 2 recovered from REST, got %+v", gap)
+		}
+	default:
+		t.Fatal("expected a gap event")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for bm.Status("PF_XBTUSD").Recoveries != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	b := bm.GetBook("PF_XBTUSD")
+	status := bm.Status("PF_XBTUSD")
+	if status.Recovering || status.Recoveries != 1 || b.Bids.Len() != 2 || b.BestAsk().Quantity.Int64() != 3 {
+		t.Errorf("expected the book to be loaded from REST, got %+v", status)
+	}
+	if err := update(bookDelta(40, 97, 1)); err != nil {
+		t.Fatalf("delta: %s", err)
+	}
+	if err := update(bookDelta(41, 96, 1)); err != nil {
+		t.Fatalf("delta: %s", err)
+	}
+	if status := bm.Status("PF_XBTUSD"); status.Sequence != 41 || status.Stale != 0 || b.Bids.Len() != 4 {
+		t.Errorf("expected the deltas following the REST snapshot to be applied, got %+v", status)
+	}
+}
+
+func TestBookManagerRecoveryFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":"error","error":"apiLimitExceeded"}`))
+	}))
+	defer server.Close()
+	bm := NewBookManager()
+	bm.ReorderWindow = 2
+	bm.WebSocket = NewWebSocket()
+	update := func(m map[string]any) error {
+		return bm.Update(bookMessage(t, m))
+	}
+	if err := update(bookSnapshot(1)); err != nil {
+		t.Fatalf("snapshot: %s", err)
+	}
+	for seq := int64(3); seq <= 4; seq++ {
+		if err := update(bookDelta(seq, 98, 1)); err != nil {
+			t.Fatalf("delta: %s", err)
+		}
+	}
+	if err := update(bookDelta(5, 98, 1)); err == nil {
+		t.Fatal("expected resubscribing without a connection to fail")
+	}
+	if err := update(bookDelta(20, 97, 1)); err != nil {
+		t.Fatalf("delta: %s", err)
+	}
+	b := bm.GetBook("PF_XBTUSD")
+	if status := bm.Status("PF_XBTUSD"); status.Recovering || status.Sequence != 20 || b.Bids.Len() != 2 {
+		t.Errorf("expected the book to continue past the gap, got %+v", status)
+	}
+
+	bm.REST = NewREST()
+	bm.REST.BaseURL = server.URL
+	errs := make(chan error, 1)
+	bm.OnError.Recurring(func(e *callback.Event[error]) {
+		errs <- e.Data
+	})
+	for seq := int64(22); seq <= 24; seq++ {
+		_ = update(bookDelta(seq, 96, 1))
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, kraken.ErrRateLimitExceeded) {
+			t.Errorf("expected the REST error, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for OnError")
+	}
+	if status := bm.Status("PF_XBTUSD"); status.Recovering || status.Gaps != 2 || status.Recoveries != 0 {
+		t.Errorf("expected the book to stop recovering after REST failed, got %+v", status)
+	}
+}
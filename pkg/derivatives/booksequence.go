@@ -0,0 +1,278 @@
+package derivatives
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/krakenfx/api-go/v2/internal/helper"
+	"github.com/krakenfx/api-go/v2/pkg/book"
+)
+
+// ErrSequenceGap is returned when deltas of a book are missing and there is no way to retrieve a new snapshot.
+// The buffered deltas are applied past the gap, so the book may differ from the one of the exchange until the next snapshot.
+var ErrSequenceGap = errors.New("book sequence gap")
+
+// Default number of out-of-order deltas buffered per product.
+const DefaultReorderWindow = 10
+
+// BookStatus contains the sequence tracking state of a managed book.
+type BookStatus struct {
+	// Sequence number of the last applied message.
+	Sequence int64 `json:"sequence,omitempty"`
+
+	// Number of deltas buffered until the missing ones arrive.
+	Pending int `json:"pending,omitempty"`
+
+	// Whether the book awaits a new snapshot after a gap.
+	Recovering bool `json:"recovering,omitempty"`
+
+	Gaps       int `json:"gaps,omitempty"`
+	Recoveries int `json:"recoveries,omitempty"`
+
+	// Number of duplicate or outdated deltas ignored.
+	Stale int `json:"stale,omitempty"`
+
+	// Number of deltas discarded while recovering.
+	Discarded int `json:"discarded,omitempty"`
+}
+
+// GapEvent is emitted by [BookManager.OnGap] when the deltas of a book cannot be reordered within the window.
+type GapEvent struct {
+	Book     *book.Book `json:"-"`
+	Expected int64      `json:"expected,omitempty"`
+	Received int64      `json:"received,omitempty"`
+
+	// How the book is recovered, which is "resubscribe", "rest" or empty if the buffered deltas are applied past the gap.
+	Recovery string `json:"recovery,omitempty"`
+}
+
+// sequence tracks the deltas of a book after a snapshot.
+type sequence struct {
+	status BookStatus
+	// Whether the next delta is accepted as is because the snapshot was retrieved from REST without a sequence number.
+	baseline bool
+	pending  map[int64]map[string]any
+}
+
+// sequenced returns the sequence number of a message.
+func sequenced(m map[string]any) (int64, bool) {
+	seq, err := helper.Traverse[json.Number](m, "seq")
+	if err != nil {
+		return 0, false
+	}
+	v, err := seq.Int64()
+	return v, err == nil
+}
+
+// applySnapshot replaces the content of a book with a snapshot and applies the buffered deltas that follow it.
+func (bm *BookManager) applySnapshot(b *book.Book, m map[string]any) error {
+	b.Clear()
+	if err := bm.UpdateSnapshot(b, m); err != nil {
+		return err
+	}
+	seq, ok := sequenced(m)
+	if !ok || bm.ReorderWindow <= 0 {
+		bm.seqMux.Lock()
+		delete(bm.sequences, b.Name)
+		bm.seqMux.Unlock()
+		return nil
+	}
+	bm.seqMux.Lock()
+	s := bm.sequence(b.Name)
+	if s.status.Recovering {
+		s.status.Recovering = false
+		s.status.Recoveries++
+	}
+	s.status.Sequence = seq
+	s.baseline = false
+	deltas := s.drain()
+	bm.seqMux.Unlock()
+	return bm.applyDeltas(b, deltas)
+}
+
+// applyDelta applies a delta in sequence, buffering it if previous deltas are missing and recovering the book if they do not arrive within the window.
+func (bm *BookManager) applyDelta(b *book.Book, m map[string]any) error {
+	seq, ok := sequenced(m)
+	bm.seqMux.Lock()
+	s, tracked := bm.sequences[b.Name]
+	if !ok || !tracked {
+		bm.seqMux.Unlock()
+		return bm.UpdateDelta(b, m)
+	}
+	var deltas []map[string]any
+	var gap *GapEvent
+	switch {
+	case s.status.Recovering:
+		s.status.Discarded++
+	case s.baseline:
+		s.baseline = false
+		s.status.Sequence = seq
+		deltas = []map[string]any{m}
+	case seq <= s.status.Sequence:
+		s.status.Stale++
+	case seq == s.status.Sequence+1:
+		s.status.Sequence = seq
+		deltas = append([]map[string]any{m}, s.drain()...)
+	default:
+		s.pending[seq] = m
+		s.status.Pending = len(s.pending)
+		if len(s.pending) > bm.ReorderWindow {
+			gap = bm.gap(b, s, seq)
+			if gap.Recovery == "" {
+				deltas = s.skip()
+			}
+		}
+	}
+	bm.seqMux.Unlock()
+	if err := bm.applyDeltas(b, deltas); err != nil {
+		return err
+	}
+	if gap != nil {
+		return bm.recover(gap)
+	}
+	return nil
+}
+
+func (bm *BookManager) applyDeltas(b *book.Book, deltas []map[string]any) error {
+	for _, delta := range deltas {
+		if err := bm.UpdateDelta(b, delta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sequence returns the sequence of a book while seqMux is locked, creating it if needed.
+func (bm *BookManager) sequence(name string) *sequence {
+	s, ok := bm.sequences[name]
+	if !ok {
+		s = &sequence{pending: make(map[int64]map[string]any)}
+		bm.sequences[name] = s
+	}
+	return s
+}
+
+// drain removes the buffered deltas following the last sequence number in order and drops the outdated ones.
+func (s *sequence) drain() []map[string]any {
+	var deltas []map[string]any
+	for seq := range s.pending {
+		if seq <= s.status.Sequence {
+			delete(s.pending, seq)
+		}
+	}
+	for {
+		delta, ok := s.pending[s.status.Sequence+1]
+		if !ok {
+			break
+		}
+		delete(s.pending, s.status.Sequence+1)
+		s.status.Sequence++
+		deltas = append(deltas, delta)
+	}
+	s.status.Pending = len(s.pending)
+	return deltas
+}
+
+// skip removes the buffered deltas in order, skipping the missing ones.
+func (s *sequence) skip() []map[string]any {
+	var deltas []map[string]any
+	for _, seq := range slices.Sorted(maps.Keys(s.pending)) {
+		deltas = append(deltas, s.pending[seq])
+		s.status.Sequence = seq
+	}
+	clear(s.pending)
+	s.status.Pending = 0
+	return deltas
+}
+
+// gap records a sequence gap while seqMux is locked and marks the book as recovering if a new snapshot can be retrieved.
+func (bm *BookManager) gap(b *book.Book, s *sequence, received int64) *GapEvent {
+	s.status.Gaps++
+	event := &GapEvent{
+		Book:     b,
+		Expected: s.status.Sequence + 1,
+		Received: received,
+	}
+	switch {
+	case bm.WebSocket != nil:
+		event.Recovery = "resubscribe"
+	case bm.REST != nil:
+		event.Recovery = "rest"
+	default:
+		return event
+	}
+	s.status.Recovering = true
+	s.status.Pending = 0
+	clear(s.pending)
+	return event
+}
+
+// recover retrieves a new snapshot of a book after a gap through the linked WebSocket or REST client.
+// The REST snapshot is retrieved in a new goroutine so that the read loop is not blocked.
+// If resubscribing fails, the snapshot is retrieved from REST if available, otherwise the book stops recovering.
+func (bm *BookManager) recover(gap *GapEvent) error {
+	bm.OnGap.Call(gap)
+	name := gap.Book.Name
+	switch gap.Recovery {
+	case "resubscribe":
+		err := bm.WebSocket.UnsubBook(name)
+		if err == nil {
+			err = bm.WebSocket.SubBook(name)
+		}
+		if err == nil {
+			return nil
+		}
+		if bm.REST != nil {
+			go bm.fetch(name)
+		} else {
+			bm.abandon(name)
+		}
+		return fmt.Errorf("\"%s\" resubscribe: %w", name, err)
+	case "rest":
+		go bm.fetch(name)
+		return nil
+	default:
+		return fmt.Errorf("\"%s\" expected %d, received %d: %w", name, gap.Expected, gap.Received, ErrSequenceGap)
+	}
+}
+
+// fetch loads a snapshot of a book from REST and calls OnError if it fails.
+func (bm *BookManager) fetch(name string) {
+	resp, err := bm.REST.OrderBook(&OrderBookRequest{Symbol: name})
+	if err != nil {
+		bm.abandon(name)
+		bm.OnError.Call(fmt.Errorf("\"%s\" order book: %w", name, err))
+		return
+	}
+	bm.LoadOrderBook(name, &resp.Result.OrderBook, resp.Result.ServerTime)
+	bm.seqMux.Lock()
+	bm.sequence(name).status.Recoveries++
+	bm.seqMux.Unlock()
+}
+
+// abandon stops recovering a book after a failed recovery.
+// The next delta is applied as is, so the book continues past the gap as if it could not be recovered.
+func (bm *BookManager) abandon(name string) {
+	bm.seqMux.Lock()
+	defer bm.seqMux.Unlock()
+	if s, ok := bm.sequences[name]; ok && s.status.Recovering {
+		s.status.Recovering = false
+		s.baseline = true
+	}
+}
+
+// Status returns a copy of the sequence tracking state of the book associated with the given product or nil if it is not tracked.
+func (bm *BookManager) Status(productID string) *BookStatus {
+	bm.seqMux.Lock()
+	defer bm.seqMux.Unlock()
+	s, ok := bm.sequences[strings.ToUpper(productID)]
+	if !ok {
+		return nil
+	}
+	status := s.status
+	return &status
+}
@@ -0,0 +1,131 @@
+package derivatives
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// Dispatcher decodes WebSocket feed messages into typed structs and calls the callback manager of their feed or event.
+// Snapshots are passed to the same manager as updates unless they have a different structure.
+// Messages are only decoded if the manager has registered callbacks.
+type Dispatcher struct {
+	OnTicker        *callback.Manager[*TickerFeed]
+	OnTickerLite    *callback.Manager[*TickerLiteFeed]
+	OnBookSnapshot  *callback.Manager[*BookSnapshotFeed]
+	OnBook          *callback.Manager[*BookFeed]
+	OnTradeSnapshot *callback.Manager[*TradeSnapshotFeed]
+	OnTrade         *callback.Manager[*TradeFeed]
+	OnFills         *callback.Manager[*FillsFeed]
+	OnOpenOrders    *callback.Manager[*OpenOrdersFeed]
+	OnOpenPositions *callback.Manager[*OpenPositionsFeed]
+	OnBalances      *callback.Manager[*BalancesFeed]
+	OnAccountLog    *callback.Manager[*AccountLogFeed]
+	OnNotifications *callback.Manager[*NotificationsFeed]
+	OnHeartbeat     *callback.Manager[*HeartbeatFeed]
+
+	OnSubscribed   *callback.Manager[*FeedEvent]
+	OnUnsubscribed *callback.Manager[*FeedEvent]
+	OnAlert        *callback.Manager[*FeedEvent]
+	OnError        *callback.Manager[*FeedEvent]
+	OnInfo         *callback.Manager[*FeedEvent]
+
+	// Called with messages that could not be decoded when the dispatcher is embedded in a [WebSocket].
+	OnDecodeError *callback.Manager[error]
+}
+
+// NewDispatcher constructs a [Dispatcher] with empty callback managers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		OnTicker:        callback.NewManager[*TickerFeed](),
+		OnTickerLite:    callback.NewManager[*TickerLiteFeed](),
+		OnBookSnapshot:  callback.NewManager[*BookSnapshotFeed](),
+		OnBook:          callback.NewManager[*BookFeed](),
+		OnTradeSnapshot: callback.NewManager[*TradeSnapshotFeed](),
+		OnTrade:         callback.NewManager[*TradeFeed](),
+		OnFills:         callback.NewManager[*FillsFeed](),
+		OnOpenOrders:    callback.NewManager[*OpenOrdersFeed](),
+		OnOpenPositions: callback.NewManager[*OpenPositionsFeed](),
+		OnBalances:      callback.NewManager[*BalancesFeed](),
+		OnAccountLog:    callback.NewManager[*AccountLogFeed](),
+		OnNotifications: callback.NewManager[*NotificationsFeed](),
+		OnHeartbeat:     callback.NewManager[*HeartbeatFeed](),
+		OnSubscribed:    callback.NewManager[*FeedEvent](),
+		OnUnsubscribed:  callback.NewManager[*FeedEvent](),
+		OnAlert:         callback.NewManager[*FeedEvent](),
+		OnError:         callback.NewManager[*FeedEvent](),
+		OnInfo:          callback.NewManager[*FeedEvent](),
+		OnDecodeError:   callback.NewManager[error](),
+	}
+}
+
+// Dispatch decodes a message and passes it to the callback manager of its feed or event.
+// Messages of unknown feeds and events are ignored.
+func (d *Dispatcher) Dispatch(m *kraken.WebSocketMessage) error {
+	data, err := m.Map()
+	if err != nil {
+		return err
+	}
+	if event, ok := data["event"].(string); ok {
+		switch event {
+		case "subscribed":
+			return dispatch(m, d.OnSubscribed)
+		case "unsubscribed":
+			return dispatch(m, d.OnUnsubscribed)
+		case "alert":
+			return dispatch(m, d.OnAlert)
+		case "error":
+			return dispatch(m, d.OnError)
+		case "info":
+			return dispatch(m, d.OnInfo)
+		}
+		return nil
+	}
+	feed, _ := data["feed"].(string)
+	switch feed {
+	case "ticker":
+		return dispatch(m, d.OnTicker)
+	case "ticker_lite":
+		return dispatch(m, d.OnTickerLite)
+	case "book_snapshot":
+		return dispatch(m, d.OnBookSnapshot)
+	case "book":
+		return dispatch(m, d.OnBook)
+	case "trade_snapshot":
+		return dispatch(m, d.OnTradeSnapshot)
+	case "trade":
+		return dispatch(m, d.OnTrade)
+	case "heartbeat":
+		return dispatch(m, d.OnHeartbeat)
+	}
+	switch strings.TrimSuffix(feed, "_snapshot") {
+	case "fills":
+		return dispatch(m, d.OnFills)
+	case "open_orders_verbose":
+		return dispatch(m, d.OnOpenOrders)
+	case "open_positions":
+		return dispatch(m, d.OnOpenPositions)
+	case "balances":
+		return dispatch(m, d.OnBalances)
+	case "account_log":
+		return dispatch(m, d.OnAccountLog)
+	case "notifications_auth":
+		return dispatch(m, d.OnNotifications)
+	}
+	return nil
+}
+
+// dispatch decodes a message into T and calls manager if it has callbacks.
+func dispatch[T any](m *kraken.WebSocketMessage, manager *callback.Manager[*T]) error {
+	if manager.Len() == 0 {
+		return nil
+	}
+	var v T
+	if err := m.JSON(&v); err != nil {
+		return fmt.Errorf("decode %T: %w", v, err)
+	}
+	manager.Call(&v)
+	return nil
+}
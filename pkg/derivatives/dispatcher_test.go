@@ -0,0 +1,52 @@
+package derivatives
+
+import (
+	"testing"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+func TestDispatcher(t *testing.T) {
+	d := NewDispatcher()
+	var snapshots []*BookSnapshotFeed
+	var deltas []*BookFeed
+	var fills []*FillsFeed
+	var errors []*FeedEvent
+	d.OnBookSnapshot.Recurring(func(e *callback.Event[*BookSnapshotFeed]) {
+		snapshots = append(snapshots, e.Data)
+	})
+	d.OnBook.Recurring(func(e *callback.Event[*BookFeed]) {
+		deltas = append(deltas, e.Data)
+	})
+	d.OnFills.Recurring(func(e *callback.Event[*FillsFeed]) {
+		fills = append(fills, e.Data)
+	})
+	d.OnError.Recurring(func(e *callback.Event[*FeedEvent]) {
+		errors = append(errors, e.Data)
+	})
+	messages := []string{
+		`{"event":"subscribed","feed":"book","product_ids":["PI_XBTUSD"]}`,
+		`{"feed":"book_snapshot","product_id":"PI_XBTUSD","timestamp":1612269825817,"seq":326072249,"tickSize":null,"bids":[{"price":34892.5,"qty":6385}],"asks":[{"price":34911.5,"qty":20598}]}`,
+		`{"feed":"book","product_id":"PI_XBTUSD","side":"sell","seq":326094134,"price":34981,"qty":0,"timestamp":1612269953629}`,
+		`{"feed":"fills_snapshot","account":"DemoUser","fills":[{"instrument":"FI_XBTUSD_200925","time":1600256910739,"price":10937.5,"seq":36,"buy":true,"qty":5000,"order_id":"9e30258b","fill_id":"cad76f07","fill_type":"maker","fee_paid":-0.00009142857,"fee_currency":"BTC"}]}`,
+		`{"event":"error","message":"Invalid product id"}`,
+	}
+	for _, message := range messages {
+		if err := d.Dispatch(kraken.NewWebSocketMessage([]byte(message))); err != nil {
+			t.Fatalf("Dispatch(%s): %s", message, err)
+		}
+	}
+	if len(snapshots) != 1 || snapshots[0].Seq != 326072249 || snapshots[0].Bids[0].Price.String() != "34892.5" || snapshots[0].TickSize != nil {
+		t.Errorf("unexpected book snapshots %+v", snapshots)
+	}
+	if len(deltas) != 1 || deltas[0].Side != "sell" || deltas[0].Qty.Sign() != 0 {
+		t.Errorf("unexpected book deltas %+v", deltas)
+	}
+	if len(fills) != 1 || fills[0].Feed != "fills_snapshot" || len(fills[0].Fills) != 1 || !fills[0].Fills[0].Buy {
+		t.Errorf("unexpected fills %+v", fills)
+	}
+	if len(errors) != 1 || errors[0].Message != "Invalid product id" {
+		t.Errorf("unexpected errors %+v", errors)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/krakenfx/api-go/v2/pkg/decimal"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
 )
 
 type MarginSchedule struct {
@@ -208,5 +209,17 @@ type OpenOrder struct {
 
 type DerivativesResponse struct {
 	Result     string    `json:"result,omitempty"`
+	Error      string    `json:"error,omitempty"`
 	ServerTime time.Time `json:"serverTime,omitempty"`
 }
+
+// GetError returns the API error as [kraken.Error] if the result is "error".
+func (r *DerivativesResponse) GetError() error {
+	if r.Result != "error" {
+		return nil
+	}
+	if r.Error == "" {
+		return kraken.ParseDerivativesError(kraken.ErrUnknown.Code)
+	}
+	return kraken.ParseDerivativesError(r.Error)
+}
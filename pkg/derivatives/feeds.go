@@ -0,0 +1,294 @@
+package derivatives
+
+import (
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// FeedEvent is an acknowledgement or notice of the WebSocket API, e.g. `subscribed`, `alert`, or `error`.
+type FeedEvent struct {
+	Event      string   `json:"event,omitempty"`
+	Feed       string   `json:"feed,omitempty"`
+	ProductIDs []string `json:"product_ids,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Version    int      `json:"version,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/ticker
+type TickerFeed struct {
+	Feed                          string           `json:"feed,omitempty"`
+	ProductID                     string           `json:"product_id,omitempty"`
+	Time                          int64            `json:"time,omitempty"`
+	Bid                           *decimal.Decimal `json:"bid,omitempty"`
+	Ask                           *decimal.Decimal `json:"ask,omitempty"`
+	BidSize                       *decimal.Decimal `json:"bid_size,omitempty"`
+	AskSize                       *decimal.Decimal `json:"ask_size,omitempty"`
+	Volume                        *decimal.Decimal `json:"volume,omitempty"`
+	VolumeQuote                   *decimal.Decimal `json:"volumeQuote,omitempty"`
+	DTM                           int              `json:"dtm,omitempty"`
+	Leverage                      string           `json:"leverage,omitempty"`
+	Index                         *decimal.Decimal `json:"index,omitempty"`
+	Premium                       *decimal.Decimal `json:"premium,omitempty"`
+	Last                          *decimal.Decimal `json:"last,omitempty"`
+	Change                        *decimal.Decimal `json:"change,omitempty"`
+	Suspended                     bool             `json:"suspended,omitempty"`
+	Tag                           string           `json:"tag,omitempty"`
+	Pair                          string           `json:"pair,omitempty"`
+	OpenInterest                  *decimal.Decimal `json:"openInterest,omitempty"`
+	MarkPrice                     *decimal.Decimal `json:"markPrice,omitempty"`
+	MaturityTime                  int64            `json:"maturityTime,omitempty"`
+	FundingRate                   *decimal.Decimal `json:"funding_rate,omitempty"`
+	FundingRatePrediction         *decimal.Decimal `json:"funding_rate_prediction,omitempty"`
+	RelativeFundingRate           *decimal.Decimal `json:"relative_funding_rate,omitempty"`
+	RelativeFundingRatePrediction *decimal.Decimal `json:"relative_funding_rate_prediction,omitempty"`
+	NextFundingRateTime           int64            `json:"next_funding_rate_time,omitempty"`
+	PostOnly                      bool             `json:"post_only,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/ticker_lite
+type TickerLiteFeed struct {
+	Feed         string           `json:"feed,omitempty"`
+	ProductID    string           `json:"product_id,omitempty"`
+	Bid          *decimal.Decimal `json:"bid,omitempty"`
+	Ask          *decimal.Decimal `json:"ask,omitempty"`
+	Change       *decimal.Decimal `json:"change,omitempty"`
+	Premium      *decimal.Decimal `json:"premium,omitempty"`
+	Volume       *decimal.Decimal `json:"volume,omitempty"`
+	VolumeQuote  *decimal.Decimal `json:"volumeQuote,omitempty"`
+	Tag          string           `json:"tag,omitempty"`
+	Pair         string           `json:"pair,omitempty"`
+	DTM          int              `json:"dtm,omitempty"`
+	MaturityTime int64            `json:"maturityTime,omitempty"`
+}
+
+type FeedPriceLevel struct {
+	Price *decimal.Decimal `json:"price,omitempty"`
+	Qty   *decimal.Decimal `json:"qty,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/book
+type BookSnapshotFeed struct {
+	Feed      string           `json:"feed,omitempty"`
+	ProductID string           `json:"product_id,omitempty"`
+	Timestamp int64            `json:"timestamp,omitempty"`
+	Seq       int64            `json:"seq,omitempty"`
+	TickSize  *decimal.Decimal `json:"tickSize,omitempty"`
+	Bids      []FeedPriceLevel `json:"bids,omitempty"`
+	Asks      []FeedPriceLevel `json:"asks,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/book
+type BookFeed struct {
+	Feed      string           `json:"feed,omitempty"`
+	ProductID string           `json:"product_id,omitempty"`
+	Side      string           `json:"side,omitempty"`
+	Seq       int64            `json:"seq,omitempty"`
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	Qty       *decimal.Decimal `json:"qty,omitempty"`
+	Timestamp int64            `json:"timestamp,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/trade
+type TradeFeed struct {
+	Feed      string           `json:"feed,omitempty"`
+	ProductID string           `json:"product_id,omitempty"`
+	UID       string           `json:"uid,omitempty"`
+	Side      string           `json:"side,omitempty"`
+	Type      string           `json:"type,omitempty"`
+	Seq       int64            `json:"seq,omitempty"`
+	Time      int64            `json:"time,omitempty"`
+	Qty       *decimal.Decimal `json:"qty,omitempty"`
+	Price     *decimal.Decimal `json:"price,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/trade
+type TradeSnapshotFeed struct {
+	Feed      string      `json:"feed,omitempty"`
+	ProductID string      `json:"product_id,omitempty"`
+	Trades    []TradeFeed `json:"trades,omitempty"`
+}
+
+type Fill struct {
+	Instrument        string           `json:"instrument,omitempty"`
+	Time              int64            `json:"time,omitempty"`
+	Price             *decimal.Decimal `json:"price,omitempty"`
+	Seq               int64            `json:"seq,omitempty"`
+	Buy               bool             `json:"buy,omitempty"`
+	Qty               *decimal.Decimal `json:"qty,omitempty"`
+	RemainingOrderQty *decimal.Decimal `json:"remaining_order_qty,omitempty"`
+	OrderID           string           `json:"order_id,omitempty"`
+	CliOrdID          string           `json:"cli_ord_id,omitempty"`
+	FillID            string           `json:"fill_id,omitempty"`
+	FillType          string           `json:"fill_type,omitempty"`
+	FeePaid           *decimal.Decimal `json:"fee_paid,omitempty"`
+	FeeCurrency       string           `json:"fee_currency,omitempty"`
+	TakerOrderType    string           `json:"taker_order_type,omitempty"`
+	OrderType         string           `json:"order_type,omitempty"`
+}
+
+// FillsFeed contains the fills of the `fills_snapshot` and `fills` feeds.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/fills
+type FillsFeed struct {
+	Feed     string `json:"feed,omitempty"`
+	Account  string `json:"account,omitempty"`
+	Username string `json:"username,omitempty"`
+	Fills    []Fill `json:"fills,omitempty"`
+}
+
+type OpenOrderVerbose struct {
+	Instrument     string           `json:"instrument,omitempty"`
+	Time           int64            `json:"time,omitempty"`
+	LastUpdateTime int64            `json:"last_update_time,omitempty"`
+	Qty            *decimal.Decimal `json:"qty,omitempty"`
+	Filled         *decimal.Decimal `json:"filled,omitempty"`
+	LimitPrice     *decimal.Decimal `json:"limit_price,omitempty"`
+	StopPrice      *decimal.Decimal `json:"stop_price,omitempty"`
+	Type           string           `json:"type,omitempty"`
+	OrderID        string           `json:"order_id,omitempty"`
+	CliOrdID       string           `json:"cli_ord_id,omitempty"`
+	Direction      int              `json:"direction,omitempty"`
+	ReduceOnly     bool             `json:"reduce_only,omitempty"`
+	TriggerSignal  string           `json:"triggerSignal,omitempty"`
+}
+
+// OpenOrdersFeed contains the orders of `open_orders_verbose_snapshot` or a single order of `open_orders_verbose`.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/open_orders_verbose
+type OpenOrdersFeed struct {
+	Feed     string             `json:"feed,omitempty"`
+	Account  string             `json:"account,omitempty"`
+	Orders   []OpenOrderVerbose `json:"orders,omitempty"`
+	Order    *OpenOrderVerbose  `json:"order,omitempty"`
+	OrderID  string             `json:"order_id,omitempty"`
+	CliOrdID string             `json:"cli_ord_id,omitempty"`
+	IsCancel bool               `json:"is_cancel,omitempty"`
+	Reason   string             `json:"reason,omitempty"`
+}
+
+type OpenPosition struct {
+	Instrument              string           `json:"instrument,omitempty"`
+	Balance                 *decimal.Decimal `json:"balance,omitempty"`
+	PNL                     *decimal.Decimal `json:"pnl,omitempty"`
+	EntryPrice              *decimal.Decimal `json:"entry_price,omitempty"`
+	MarkPrice               *decimal.Decimal `json:"mark_price,omitempty"`
+	IndexPrice              *decimal.Decimal `json:"index_price,omitempty"`
+	LiquidationThreshold    *decimal.Decimal `json:"liquidation_threshold,omitempty"`
+	EffectiveLeverage       *decimal.Decimal `json:"effective_leverage,omitempty"`
+	ReturnOnEquity          *decimal.Decimal `json:"return_on_equity,omitempty"`
+	InitialMargin           *decimal.Decimal `json:"initial_margin,omitempty"`
+	InitialMarginWithOrders *decimal.Decimal `json:"initial_margin_with_orders,omitempty"`
+	MaintenanceMargin       *decimal.Decimal `json:"maintenance_margin,omitempty"`
+	PNLCurrency             string           `json:"pnl_currency,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/open_position
+type OpenPositionsFeed struct {
+	Feed      string         `json:"feed,omitempty"`
+	Account   string         `json:"account,omitempty"`
+	Positions []OpenPosition `json:"positions,omitempty"`
+	Seq       int64          `json:"seq,omitempty"`
+	Timestamp int64          `json:"timestamp,omitempty"`
+}
+
+type FuturesBalance struct {
+	Name              string           `json:"name,omitempty"`
+	Pair              string           `json:"pair,omitempty"`
+	Unit              string           `json:"unit,omitempty"`
+	PortfolioValue    *decimal.Decimal `json:"portfolio_value,omitempty"`
+	Balance           *decimal.Decimal `json:"balance,omitempty"`
+	MaintenanceMargin *decimal.Decimal `json:"maintenance_margin,omitempty"`
+	InitialMargin     *decimal.Decimal `json:"initial_margin,omitempty"`
+	Available         *decimal.Decimal `json:"available,omitempty"`
+	UnrealizedFunding *decimal.Decimal `json:"unrealized_funding,omitempty"`
+	PNL               *decimal.Decimal `json:"pnl,omitempty"`
+}
+
+type FlexCurrency struct {
+	Quantity         *decimal.Decimal `json:"quantity,omitempty"`
+	Value            *decimal.Decimal `json:"value,omitempty"`
+	CollateralValue  *decimal.Decimal `json:"collateral_value,omitempty"`
+	Available        *decimal.Decimal `json:"available,omitempty"`
+	Haircut          *decimal.Decimal `json:"haircut,omitempty"`
+	ConversionSpread *decimal.Decimal `json:"conversion_spread,omitempty"`
+}
+
+type FlexFuturesBalance struct {
+	Currencies                 map[string]FlexCurrency `json:"currencies,omitempty"`
+	BalanceValue               *decimal.Decimal        `json:"balance_value,omitempty"`
+	PortfolioValue             *decimal.Decimal        `json:"portfolio_value,omitempty"`
+	CollateralValue            *decimal.Decimal        `json:"collateral_value,omitempty"`
+	InitialMargin              *decimal.Decimal        `json:"initial_margin,omitempty"`
+	InitialMarginWithoutOrders *decimal.Decimal        `json:"initial_margin_without_orders,omitempty"`
+	MaintenanceMargin          *decimal.Decimal        `json:"maintenance_margin,omitempty"`
+	PNL                        *decimal.Decimal        `json:"pnl,omitempty"`
+	UnrealizedFunding          *decimal.Decimal        `json:"unrealized_funding,omitempty"`
+	TotalUnrealized            *decimal.Decimal        `json:"total_unrealized,omitempty"`
+	TotalUnrealizedAsMargin    *decimal.Decimal        `json:"total_unrealized_as_margin,omitempty"`
+	MarginEquity               *decimal.Decimal        `json:"margin_equity,omitempty"`
+	AvailableMargin            *decimal.Decimal        `json:"available_margin,omitempty"`
+}
+
+// BalancesFeed contains the wallets of the `balances_snapshot` and `balances` feeds.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/balances
+type BalancesFeed struct {
+	Feed        string                      `json:"feed,omitempty"`
+	Account     string                      `json:"account,omitempty"`
+	Holding     map[string]*decimal.Decimal `json:"holding,omitempty"`
+	Futures     map[string]FuturesBalance   `json:"futures,omitempty"`
+	FlexFutures *FlexFuturesBalance         `json:"flex_futures,omitempty"`
+	Timestamp   int64                       `json:"timestamp,omitempty"`
+	Seq         int64                       `json:"seq,omitempty"`
+}
+
+type AccountLogEntry struct {
+	ID                         int64            `json:"id,omitempty"`
+	Date                       string           `json:"date,omitempty"`
+	Asset                      string           `json:"asset,omitempty"`
+	Info                       string           `json:"info,omitempty"`
+	BookingUID                 string           `json:"booking_uid,omitempty"`
+	MarginAccount              string           `json:"margin_account,omitempty"`
+	OldBalance                 *decimal.Decimal `json:"old_balance,omitempty"`
+	NewBalance                 *decimal.Decimal `json:"new_balance,omitempty"`
+	OldAverageEntryPrice       *decimal.Decimal `json:"old_average_entry_price,omitempty"`
+	NewAverageEntryPrice       *decimal.Decimal `json:"new_average_entry_price,omitempty"`
+	TradePrice                 *decimal.Decimal `json:"trade_price,omitempty"`
+	MarkPrice                  *decimal.Decimal `json:"mark_price,omitempty"`
+	RealizedPNL                *decimal.Decimal `json:"realized_pnl,omitempty"`
+	Fee                        *decimal.Decimal `json:"fee,omitempty"`
+	Execution                  string           `json:"execution,omitempty"`
+	Collateral                 string           `json:"collateral,omitempty"`
+	FundingRate                *decimal.Decimal `json:"funding_rate,omitempty"`
+	RealizedFunding            *decimal.Decimal `json:"realized_funding,omitempty"`
+	ConversionSpreadPercentage *decimal.Decimal `json:"conversion_spread_percentage,omitempty"`
+	LiquidationFee             *decimal.Decimal `json:"liquidation_fee,omitempty"`
+}
+
+// AccountLogFeed contains the entries of `account_log_snapshot` or a single entry of `account_log`.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/account_log
+type AccountLogFeed struct {
+	Feed     string            `json:"feed,omitempty"`
+	Logs     []AccountLogEntry `json:"logs,omitempty"`
+	NewEntry *AccountLogEntry  `json:"new_entry,omitempty"`
+}
+
+type Notification struct {
+	ID            int64  `json:"id,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Priority      string `json:"priority,omitempty"`
+	Note          string `json:"note,omitempty"`
+	EffectiveTime int64  `json:"effective_time,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/notifications
+type NotificationsFeed struct {
+	Feed          string         `json:"feed,omitempty"`
+	Notifications []Notification `json:"notifications,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/futures-api/websocket/heartbeat
+type HeartbeatFeed struct {
+	Feed string `json:"feed,omitempty"`
+	Time int64  `json:"time,omitempty"`
+}
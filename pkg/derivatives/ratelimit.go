@@ -0,0 +1,135 @@
+package derivatives
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/krakenfx/api-go/v2/internal/helper"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// Cost of each endpoint under /derivatives/api/v3 against the budget.
+//
+// https://docs.kraken.com/api/docs/guides/futures-rate-limits
+var endpointCosts = map[string]float64{
+	"sendorder":            10,
+	"editorder":            10,
+	"cancelorder":          10,
+	"batchorder":           9,
+	"cancelallorders":      25,
+	"cancelallordersafter": 25,
+	"accounts":             2,
+	"openpositions":        2,
+	"fills":                2,
+	"openorders":           2,
+	"orders/status":        1,
+	"leveragepreferences":  2,
+	"pnlpreferences":       2,
+	"transfer":             100,
+	"transfer/subaccount":  10,
+	"withdrawal":           100,
+	"unwindqueue":          200,
+}
+
+// Endpoints that are not counted against the budget.
+var publicEndpoints = map[string]bool{
+	"instruments": true,
+	"tickers":     true,
+	"orderbook":   true,
+	"history":     true,
+}
+
+// RateLimiter models the cost budget of the Futures REST API, which allows a cost of 500 every 10 seconds.
+type RateLimiter struct {
+	Mode   kraken.LimitMode
+	Budget *kraken.DecayCounter
+}
+
+// NewRateLimiter constructs a [RateLimiter] with the default budget.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		Mode:   kraken.BlockMode,
+		Budget: kraken.NewDecayCounter(500, 50),
+	}
+}
+
+// Cost returns the cost of a request against the budget.
+//
+// Batch orders cost 9 plus the number of instructions and fills with a `lastFillTime` cost 25.
+func (l *RateLimiter) Cost(request *http.Request) float64 {
+	_, endpoint, found := strings.Cut(request.URL.Path, "/derivatives/api/v3/")
+	if !found {
+		return 0
+	}
+	endpoint = strings.Trim(endpoint, "/")
+	if publicEndpoints[strings.Split(endpoint, "/")[0]] {
+		return 0
+	}
+	cost, ok := endpointCosts[endpoint]
+	if !ok {
+		return 1
+	}
+	switch endpoint {
+	case "fills":
+		if request.URL.Query().Get("lastFillTime") != "" {
+			cost = 25
+		}
+	case "batchorder":
+		cost += float64(batchSize(request))
+	}
+	return cost
+}
+
+// batchSize returns the number of instructions in a batch order request.
+func batchSize(request *http.Request) int {
+	if request.GetBody == nil {
+		return 0
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return 0
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return 0
+	}
+	mediaType, _, _ := strings.Cut(request.Header.Get("Content-Type"), ";")
+	params, err := helper.Unmarshal(strings.ToLower(mediaType), data)
+	if err != nil {
+		return 0
+	}
+	var batch BatchOrderJson
+	switch v := params["json"].(type) {
+	case string:
+		if err := json.Unmarshal([]byte(v), &batch); err != nil {
+			return 0
+		}
+	case map[string]any:
+		if err := json.Unmarshal([]byte(helper.ToJSON(v)), &batch); err != nil {
+			return 0
+		}
+	}
+	return len(batch.BatchOrder)
+}
+
+// State returns a snapshot of the budget.
+func (l *RateLimiter) State() kraken.DecayCounterState {
+	return l.Budget.State()
+}
+
+// Wrap returns a [kraken.ExecutorFunction] that reserves the cost of the request on the budget before calling next.
+func (l *RateLimiter) Wrap(next kraken.ExecutorFunction) kraken.ExecutorFunction {
+	return func(request *http.Request) (*http.Response, error) {
+		if cost := l.Cost(request); cost > 0 {
+			if err := l.Budget.Reserve(request.Context(), cost, l.Mode); err != nil {
+				return nil, err
+			}
+		}
+		return next(request)
+	}
+}
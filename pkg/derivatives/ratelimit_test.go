@@ -0,0 +1,68 @@
+package derivatives
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// rateLimitRequest constructs a request to an endpoint with a form body.
+func rateLimitRequest(t *testing.T, method string, endpoint string, form url.Values) *http.Request {
+	request, err := http.NewRequest(method, "https://futures.kraken.com/derivatives/api/v3/"+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return request
+}
+
+func TestRateLimiterCost(t *testing.T) {
+	limiter := NewRateLimiter()
+	batch := url.Values{"json": {`{"batchOrder":[{"order":"send"},{"order":"send"},{"order":"cancel"}]}`}}
+	tests := []struct {
+		request  *http.Request
+		expected float64
+	}{
+		{rateLimitRequest(t, "POST", "sendorder", nil), 10},
+		{rateLimitRequest(t, "POST", "batchorder", batch), 12},
+		{rateLimitRequest(t, "POST", "cancelallorders", nil), 25},
+		{rateLimitRequest(t, "GET", "fills", nil), 2},
+		{rateLimitRequest(t, "GET", "fills?lastFillTime=2024-01-01T00:00:00Z", nil), 25},
+		{rateLimitRequest(t, "GET", "tickers/PF_XBTUSD", nil), 0},
+		{rateLimitRequest(t, "GET", "unknown", nil), 1},
+	}
+	for _, test := range tests {
+		if cost := limiter.Cost(test.request); cost != test.expected {
+			t.Errorf("%s: expected a cost of %g, got %g", test.request.URL, test.expected, cost)
+		}
+	}
+}
+
+func TestRateLimiterWrap(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.Mode = kraken.FailFastMode
+	limiter.Budget = kraken.NewDecayCounter(20, 1e-6)
+	var calls int
+	execute := limiter.Wrap(func(request *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusOK, Request: request}, nil
+	})
+	for range 2 {
+		if _, err := execute(rateLimitRequest(t, "POST", "sendorder", nil)); err != nil {
+			t.Fatalf("sendorder: %s", err)
+		}
+	}
+	if _, err := execute(rateLimitRequest(t, "GET", "tickers", nil)); err != nil {
+		t.Errorf("expected public requests not to be limited, got %v", err)
+	}
+	if _, err := execute(rateLimitRequest(t, "POST", "sendorder", nil)); !errors.Is(err, kraken.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited once the budget is used, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected the limited request not to be sent, got %d calls", calls)
+	}
+}
@@ -1,9 +1,11 @@
 package derivatives
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -17,7 +19,10 @@ type REST struct {
 	PrivateKey string
 	Nonce      func() string
 	BaseURL    string
+	Client     *http.Client
 	Executor   kraken.ExecutorFunction
+	Limiter    *RateLimiter
+	Retry      *kraken.RetryPolicy
 }
 
 // REST constructs a new [REST] object with default values.
@@ -26,15 +31,21 @@ type REST struct {
 func NewREST() *REST {
 	return &REST{
 		BaseURL: "https://futures.kraken.com",
+		Client:  kraken.DefaultClient,
 	}
 }
 
 func (r *REST) NewRequest(opts RequestOptions) (*kraken.Request, error) {
-	return NewRequest(RequestOptions{
+	nonce := opts.Nonce
+	if nonce == nil {
+		nonce = r.Nonce
+	}
+	req, err := NewRequest(RequestOptions{
+		Context:    opts.Context,
 		Auth:       opts.Auth,
 		PublicKey:  r.PublicKey,
 		PrivateKey: r.PrivateKey,
-		Nonce:      opts.Nonce,
+		Nonce:      nonce,
 		Method:     opts.Method,
 		URL:        r.BaseURL,
 		Path:       opts.Path,
@@ -42,8 +53,12 @@ func (r *REST) NewRequest(opts RequestOptions) (*kraken.Request, error) {
 		Headers:    opts.Headers,
 		Body:       opts.Body,
 		UserAgent:  opts.UserAgent,
-		Executor:   r.Executor,
+		Executor:   r.executor(),
 	})
+	if err == nil && r.Limiter != nil {
+		req.Executor = r.Limiter.Wrap(req.Executor)
+	}
+	return req, err
 }
 
 type InstrumentResult struct {
@@ -55,7 +70,12 @@ type InstrumentResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/get-instruments
 func (r *REST) Instruments() (*Response[InstrumentResult], error) {
-	return Call[InstrumentResult](r, RequestOptions{
+	return r.InstrumentsContext(context.Background())
+}
+
+// InstrumentsContext is like [REST.Instruments] but includes a context.
+func (r *REST) InstrumentsContext(ctx context.Context) (*Response[InstrumentResult], error) {
+	return CallContext[InstrumentResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/derivatives/api/v3/instruments",
 	})
@@ -63,7 +83,12 @@ func (r *REST) Instruments() (*Response[InstrumentResult], error) {
 
 // InstrumentSymbol calls [REST.Instruments] and returns the first [Instrument] with matching symbol.
 func (r *REST) InstrumentSymbol(s string) (*Instrument, error) {
-	resp, err := r.Instruments()
+	return r.InstrumentSymbolContext(context.Background(), s)
+}
+
+// InstrumentSymbolContext is like [REST.InstrumentSymbol] but includes a context.
+func (r *REST) InstrumentSymbolContext(ctx context.Context, s string) (*Instrument, error) {
+	resp, err := r.InstrumentsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +109,12 @@ type TickersResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/get-tickers
 func (r *REST) Tickers() (*Response[TickersResult], error) {
-	return Call[TickersResult](r, RequestOptions{
+	return r.TickersContext(context.Background())
+}
+
+// TickersContext is like [REST.Tickers] but includes a context.
+func (r *REST) TickersContext(ctx context.Context) (*Response[TickersResult], error) {
+	return CallContext[TickersResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/derivatives/api/v3/tickers",
 	})
@@ -97,11 +127,24 @@ type TickersSingleResult struct {
 	DerivativesResponse
 }
 
+// GetError returns the API error as [kraken.Error] if the result reports a failure.
+func (r *TickersSingleResult) GetError() error {
+	if r.Error != nil && r.DerivativesResponse.Error == "" {
+		r.DerivativesResponse.Error = fmt.Sprint(r.Error)
+	}
+	return r.DerivativesResponse.GetError()
+}
+
 // TickerSymbol retrieves the ticker information of a specific contract pair or indice.
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/get-ticker
 func (r *REST) TickerSymbol(symbol string) (*Response[TickersSingleResult], error) {
-	return Call[TickersSingleResult](r, RequestOptions{
+	return r.TickerSymbolContext(context.Background(), symbol)
+}
+
+// TickerSymbolContext is like [REST.TickerSymbol] but includes a context.
+func (r *REST) TickerSymbolContext(ctx context.Context, symbol string) (*Response[TickersSingleResult], error) {
+	return CallContext[TickersSingleResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   []any{"/derivatives/api/v3/tickers/", symbol},
 		Auth:   false,
@@ -121,7 +164,12 @@ type OrderBookResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/get-orderbook
 func (r *REST) OrderBook(opts *OrderBookRequest) (*Response[OrderBookResult], error) {
-	return Call[OrderBookResult](r, RequestOptions{
+	return r.OrderBookContext(context.Background(), opts)
+}
+
+// OrderBookContext is like [REST.OrderBook] but includes a context.
+func (r *REST) OrderBookContext(ctx context.Context, opts *OrderBookRequest) (*Response[OrderBookResult], error) {
+	return CallContext[OrderBookResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   []any{"/derivatives/api/v3/orderbook"},
 		Query:  opts,
@@ -142,7 +190,12 @@ type TradeHistoryResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/get-history
 func (r *REST) TradeHistory(opts *TradeHistoryRequest) (*Response[TradeHistoryResult], error) {
-	return Call[TradeHistoryResult](r, RequestOptions{
+	return r.TradeHistoryContext(context.Background(), opts)
+}
+
+// TradeHistoryContext is like [REST.TradeHistory] but includes a context.
+func (r *REST) TradeHistoryContext(ctx context.Context, opts *TradeHistoryRequest) (*Response[TradeHistoryResult], error) {
+	return CallContext[TradeHistoryResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/derivatives/api/v3/history",
 		Query:  opts,
@@ -158,7 +211,12 @@ type AccountsResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/get-accounts
 func (r *REST) Accounts() (*Response[AccountsResult], error) {
-	return Call[AccountsResult](r, RequestOptions{
+	return r.AccountsContext(context.Background())
+}
+
+// AccountsContext is like [REST.Accounts] but includes a context.
+func (r *REST) AccountsContext(ctx context.Context) (*Response[AccountsResult], error) {
+	return CallContext[AccountsResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/derivatives/api/v3/accounts",
 		Auth:   true,
@@ -191,7 +249,12 @@ type SendOrderResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/send-order
 func (r *REST) SendOrder(opts *OrderRequest) (*Response[SendOrderResult], error) {
-	return Call[SendOrderResult](r, RequestOptions{
+	return r.SendOrderContext(context.Background(), opts)
+}
+
+// SendOrderContext is like [REST.SendOrder] but includes a context.
+func (r *REST) SendOrderContext(ctx context.Context, opts *OrderRequest) (*Response[SendOrderResult], error) {
+	return CallContext[SendOrderResult](ctx, r, RequestOptions{
 		Method: "POST",
 		Path:   "/derivatives/api/v3/sendorder",
 		Body:   opts,
@@ -213,7 +276,12 @@ type BatchOrderResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/send-batch-order
 func (r *REST) BatchOrder(opts *BatchOrderRequest) (*Response[BatchOrderResult], error) {
-	return Call[BatchOrderResult](r, RequestOptions{
+	return r.BatchOrderContext(context.Background(), opts)
+}
+
+// BatchOrderContext is like [REST.BatchOrder] but includes a context.
+func (r *REST) BatchOrderContext(ctx context.Context, opts *BatchOrderRequest) (*Response[BatchOrderResult], error) {
+	return CallContext[BatchOrderResult](ctx, r, RequestOptions{
 		Method: "POST",
 		Path:   "/derivatives/api/v3/batchorder",
 		Body:   opts,
@@ -230,7 +298,12 @@ type EditOrderResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/edit-order-spring
 func (r *REST) EditOrder(opts *OrderRequest) (*Response[EditOrderResult], error) {
-	return Call[EditOrderResult](r, RequestOptions{
+	return r.EditOrderContext(context.Background(), opts)
+}
+
+// EditOrderContext is like [REST.EditOrder] but includes a context.
+func (r *REST) EditOrderContext(ctx context.Context, opts *OrderRequest) (*Response[EditOrderResult], error) {
+	return CallContext[EditOrderResult](ctx, r, RequestOptions{
 		Method: "POST",
 		Path:   "/derivatives/api/v3/editorder",
 		Body:   opts,
@@ -253,7 +326,12 @@ type CancelOrderResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/cancel-order
 func (r *REST) CancelOrder(opts *CancelOrderRequest) (*Response[CancelOrderResult], error) {
-	return Call[CancelOrderResult](r, RequestOptions{
+	return r.CancelOrderContext(context.Background(), opts)
+}
+
+// CancelOrderContext is like [REST.CancelOrder] but includes a context.
+func (r *REST) CancelOrderContext(ctx context.Context, opts *CancelOrderRequest) (*Response[CancelOrderResult], error) {
+	return CallContext[CancelOrderResult](ctx, r, RequestOptions{
 		Method: "POST",
 		Path:   []any{"/derivatives/api/v3/cancelorder"},
 		Body:   opts,
@@ -274,7 +352,12 @@ type CancelAllResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/cancel-all-orders
 func (r *REST) CancelAll(opts *CancelAllRequest) (*Response[CancelAllResult], error) {
-	return Call[CancelAllResult](r, RequestOptions{
+	return r.CancelAllContext(context.Background(), opts)
+}
+
+// CancelAllContext is like [REST.CancelAll] but includes a context.
+func (r *REST) CancelAllContext(ctx context.Context, opts *CancelAllRequest) (*Response[CancelAllResult], error) {
+	return CallContext[CancelAllResult](ctx, r, RequestOptions{
 		Method: "POST",
 		Path:   "/derivatives/api/v3/cancelallorders",
 		Body:   opts,
@@ -291,7 +374,12 @@ type OpenOrdersResult struct {
 //
 // https://docs.kraken.com/api/docs/futures-api/trading/get-open-orders
 func (r *REST) OpenOrders() (*Response[OpenOrdersResult], error) {
-	return Call[OpenOrdersResult](r, RequestOptions{
+	return r.OpenOrdersContext(context.Background())
+}
+
+// OpenOrdersContext is like [REST.OpenOrders] but includes a context.
+func (r *REST) OpenOrdersContext(ctx context.Context) (*Response[OpenOrdersResult], error) {
+	return CallContext[OpenOrdersResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/derivatives/api/v3/openorders",
 		Auth:   true,
@@ -302,8 +390,80 @@ type Requestor interface {
 	NewRequest(RequestOptions) (*kraken.Request, error)
 }
 
+// executor returns [REST.Executor] or the Do method of [REST.Client] if it is not set.
+func (r *REST) executor() kraken.ExecutorFunction {
+	if r.Executor == nil && r.Client != nil {
+		return r.Client.Do
+	}
+	return r.Executor
+}
+
+// GetRetryPolicy implements [kraken.RetryPolicyProvider].
+func (r *REST) GetRetryPolicy() *kraken.RetryPolicy {
+	return r.Retry
+}
+
+// Endpoints whose repeated submission creates duplicate orders or transfers.
+var nonIdempotentEndpoints = map[string]bool{
+	"sendorder":           true,
+	"batchorder":          true,
+	"transfer":            true,
+	"transfer/subaccount": true,
+	"withdrawal":          true,
+}
+
+// IsIdempotent reports whether the request can be resubmitted when the outcome of a previous attempt is unknown.
+//
+// Order placement is only considered idempotent if every new order has a `cliOrdId` to reconcile duplicates with.
+func IsIdempotent(opts RequestOptions) bool {
+	segments, err := helper.StringSlice(opts.Path)
+	if err != nil || len(segments) == 0 {
+		return false
+	}
+	_, endpoint, _ := strings.Cut(strings.Join(segments, "/"), "/derivatives/api/v3/")
+	endpoint = strings.Trim(endpoint, "/")
+	if !nonIdempotentEndpoints[endpoint] {
+		return true
+	}
+	switch body := opts.Body.(type) {
+	case *OrderRequest:
+		return endpoint == "sendorder" && body != nil && body.ClientOrderID != ""
+	case *BatchOrderRequest:
+		if endpoint != "batchorder" || body == nil || body.JSON == nil {
+			return false
+		}
+		for _, instruction := range body.JSON.BatchOrder {
+			if instruction.Order == "send" && instruction.ClientOrderID == "" {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
 // Call creates a request and returns a generic response.
+//
+// If r implements [kraken.RetryPolicyProvider], failed calls are retried with a newly signed request per attempt.
+// See [IsIdempotent] for the requests that are retried when the outcome is unknown.
 func Call[T any](r Requestor, opts RequestOptions) (resp *Response[T], err error) {
+	provider, ok := r.(kraken.RetryPolicyProvider)
+	if !ok || provider.GetRetryPolicy() == nil {
+		return call[T](r, opts)
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	err = provider.GetRetryPolicy().Do(ctx, IsIdempotent(opts), func() (*kraken.Response, error) {
+		resp, err = call[T](r, opts)
+		return resp.Http, err
+	})
+	return resp, err
+}
+
+func call[T any](r Requestor, opts RequestOptions) (resp *Response[T], err error) {
 	resp = &Response[T]{}
 	req, err := r.NewRequest(opts)
 	if err != nil {
@@ -313,11 +473,21 @@ func Call[T any](r Requestor, opts RequestOptions) (resp *Response[T], err error
 	if err != nil {
 		return resp, err
 	}
-	return resp, resp.Http.JSON(&resp.Result)
+	if err := resp.Http.JSON(&resp.Result); err != nil {
+		return resp, err
+	}
+	return resp, resp.GetError()
+}
+
+// CallContext is like [Call] but includes a context.
+func CallContext[T any](ctx context.Context, r Requestor, opts RequestOptions) (*Response[T], error) {
+	opts.Context = ctx
+	return Call[T](r, opts)
 }
 
 // RequestOptions contains the parameters for [NewRequest].
 type RequestOptions struct {
+	Context    context.Context
 	Auth       bool
 	PublicKey  string
 	PrivateKey string
@@ -337,6 +507,7 @@ type RequestOptions struct {
 // Authentication algorithm: https://docs.kraken.com/api/docs/guides/futures-rest
 func NewRequest(opts RequestOptions) (*kraken.Request, error) {
 	request, err := kraken.NewRequestWithOptions(kraken.RequestOptions{
+		Context:   opts.Context,
 		Method:    opts.Method,
 		URL:       opts.URL,
 		Headers:   opts.Headers,
@@ -392,3 +563,11 @@ type Response[T any] struct {
 	Result T                `json:"result,omitempty"`
 	Http   *kraken.Response `json:"-"`
 }
+
+// GetError returns the API error as [kraken.Error] if the result reports a failure.
+func (r *Response[T]) GetError() error {
+	if getter, ok := any(&r.Result).(interface{ GetError() error }); ok {
+		return getter.GetError()
+	}
+	return nil
+}
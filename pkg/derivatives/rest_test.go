@@ -0,0 +1,41 @@
+package derivatives
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// stubExecutor returns an executor answering every request with the given body.
+func stubExecutor(body string) kraken.ExecutorFunction {
+	return func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    request,
+		}, nil
+	}
+}
+
+func TestRESTError(t *testing.T) {
+	rest := NewREST()
+	rest.Executor = stubExecutor(`{"result":"error","error":"apiLimitExceeded","serverTime":"2024-01-01T00:00:00Z"}`)
+	_, err := rest.Tickers()
+	var apiErr *kraken.Error
+	if !errors.Is(err, kraken.ErrRateLimitExceeded) || !errors.As(err, &apiErr) || apiErr.Code != "apiLimitExceeded" {
+		t.Errorf("expected a rate limit error, got %v", err)
+	}
+	rest.Executor = stubExecutor(`{"result":"error","error":"apiLimitExceeded"}`)
+	if _, err := rest.TickerSymbol("PF_XBTUSD"); !errors.Is(err, kraken.ErrRateLimitExceeded) {
+		t.Errorf("expected a rate limit error from the ticker, got %v", err)
+	}
+	rest.Executor = stubExecutor(`{"result":"success","tickers":[]}`)
+	if _, err := rest.Tickers(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
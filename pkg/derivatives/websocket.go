@@ -1,9 +1,15 @@
 package derivatives
 
+import (
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
 // WebSocket wraps a [WebSocketBase] struct with order management and subscription request functions.
 type WebSocket struct {
 	REST *REST
 	*WebSocketBase
+	*Dispatcher
 }
 
 // NewWebSocket constructs a new [WebSocket] struct with default values.
@@ -13,7 +19,13 @@ func NewWebSocket() *WebSocket {
 	ws := &WebSocket{
 		REST:          NewREST(),
 		WebSocketBase: NewWebSocketBase(),
+		Dispatcher:    NewDispatcher(),
 	}
+	ws.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
+		if err := ws.Dispatch(e.Data); err != nil {
+			ws.OnDecodeError.Call(err)
+		}
+	})
 	return ws
 }
 
@@ -42,37 +54,87 @@ func (s *WebSocket) SubExecutions() error {
 //
 // https://docs.kraken.com/api/docs/futures-api/websocket/ticker
 func (s *WebSocket) SubTicker(productID ...string) error {
-	var productSubscription map[string]any
-	if len(productID) > 0 {
-		productSubscription = map[string]any{
-			"product_ids": productID,
-		}
-	}
-	return s.SubPublic("ticker", productSubscription)
+	return s.SubPublic("ticker", productIDs(productID))
 }
 
 // SubBook sends a subscription request to retrieve information about the order book.
 //
 // https://docs.kraken.com/api/docs/futures-api/websocket/book
 func (s *WebSocket) SubBook(productID ...string) error {
-	var productSubscription map[string]any
-	if len(productID) > 0 {
-		productSubscription = map[string]any{
-			"product_ids": productID,
-		}
-	}
-	return s.SubPublic("book", productSubscription)
+	return s.SubPublic("book", productIDs(productID))
 }
 
 // SubTrade sends a subscription request to retrieve information about executed trades.
 //
 // https://docs.kraken.com/api/docs/futures-api/websocket/trade
 func (s *WebSocket) SubTrade(productID ...string) error {
-	var productSubscription map[string]any
-	if len(productID) > 0 {
-		productSubscription = map[string]any{
-			"product_ids": productID,
-		}
+	return s.SubPublic("trade", productIDs(productID))
+}
+
+// SubHeartbeat sends a subscription request to receive a heartbeat message every 5 seconds.
+// The client already subscribes once per connection through [kraken.WebSocket.Heartbeat] after the first PingInterval,
+// so this is only needed to receive heartbeats immediately or when Heartbeat is replaced.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/heartbeat
+func (s *WebSocket) SubHeartbeat() error {
+	return s.SubPublic("heartbeat")
+}
+
+// UnsubBalances sends an unsubscribe request for the balances feed.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/balances
+func (s *WebSocket) UnsubBalances() error {
+	return s.UnsubPrivate("balances")
+}
+
+// UnsubOpenOrders sends an unsubscribe request for the open orders feed.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/open_orders_verbose
+func (s *WebSocket) UnsubOpenOrders() error {
+	return s.UnsubPrivate("open_orders_verbose")
+}
+
+// UnsubExecutions sends an unsubscribe request for the fills feed.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/fills
+func (s *WebSocket) UnsubExecutions() error {
+	return s.UnsubPrivate("fills")
+}
+
+// UnsubTicker sends an unsubscribe request for the ticker of the specified products.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/ticker
+func (s *WebSocket) UnsubTicker(productID ...string) error {
+	return s.UnsubPublic("ticker", productIDs(productID))
+}
+
+// UnsubBook sends an unsubscribe request for the order book of the specified products.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/book
+func (s *WebSocket) UnsubBook(productID ...string) error {
+	return s.UnsubPublic("book", productIDs(productID))
+}
+
+// UnsubTrade sends an unsubscribe request for the trades of the specified products.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/trade
+func (s *WebSocket) UnsubTrade(productID ...string) error {
+	return s.UnsubPublic("trade", productIDs(productID))
+}
+
+// UnsubHeartbeat sends an unsubscribe request for the heartbeat feed.
+//
+// https://docs.kraken.com/api/docs/futures-api/websocket/heartbeat
+func (s *WebSocket) UnsubHeartbeat() error {
+	return s.UnsubPublic("heartbeat")
+}
+
+// productIDs returns the `product_ids` option of a request or nil if there are none.
+func productIDs(productID []string) map[string]any {
+	if len(productID) == 0 {
+		return nil
+	}
+	return map[string]any{
+		"product_ids": productID,
 	}
-	return s.SubPublic("trade", productSubscription)
 }
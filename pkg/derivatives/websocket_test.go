@@ -0,0 +1,135 @@
+package derivatives
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+// newTestServer starts a WebSocket server that replies to each request with the result of handler.
+func newTestServer(t *testing.T, handler func(request map[string]any) []any) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %s", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		for {
+			var request map[string]any
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			for _, response := range handler(request) {
+				if err := conn.WriteJSON(response); err != nil {
+					return
+				}
+			}
+		}
+	}))
+}
+
+func TestWebSocketAuthenticate(t *testing.T) {
+	var quiet atomic.Bool
+	server := newTestServer(t, func(request map[string]any) []any {
+		if request["event"] != "challenge" || quiet.Load() {
+			return nil
+		}
+		return []any{map[string]any{"event": "challenge", "message": "c100b894-1729-464d-ace1-52dbce11db42"}}
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	ws.PublicKey = "key"
+	ws.PrivateKey = "c2VjcmV0"
+	ws.AuthenticateTimeout = 100 * time.Millisecond
+	authenticated := make(chan string, 1)
+	ws.OnAuthenticated.Recurring(func(e *callback.Event[string]) {
+		authenticated <- e.Data
+	})
+	failed := make(chan error, 1)
+	ws.OnAuthFailed.Recurring(func(e *callback.Event[error]) {
+		failed <- e.Data
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	if err := ws.AuthenticateAsync(); err != nil {
+		t.Fatalf("AuthenticateAsync: %s", err)
+	}
+	select {
+	case challenge := <-authenticated:
+		if _, signature := ws.CurrentChallenge(); challenge != "c100b894-1729-464d-ace1-52dbce11db42" || signature == "" {
+			t.Errorf("expected a signed challenge, got %q signed as %q", challenge, signature)
+		}
+	case err := <-failed:
+		t.Fatalf("expected authentication to succeed, got %s", err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for OnAuthenticated")
+	}
+	quiet.Store(true)
+	if err := ws.Authenticate(); !errors.Is(err, ErrAuthenticationTimeout) {
+		t.Errorf("expected ErrAuthenticationTimeout, got %v", err)
+	}
+	if err := <-failed; !errors.Is(err, ErrAuthenticationTimeout) {
+		t.Errorf("expected OnAuthFailed with ErrAuthenticationTimeout, got %v", err)
+	}
+}
+
+func TestWebSocketHeartbeat(t *testing.T) {
+	subscriptions := make(chan struct{}, 10)
+	server := newTestServer(t, func(request map[string]any) []any {
+		if request["event"] == "subscribe" && request["feed"] == "heartbeat" {
+			subscriptions <- struct{}{}
+		}
+		return nil
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	ws.PingInterval = 10 * time.Millisecond
+	for range 2 {
+		if err := ws.Connect(); err != nil {
+			t.Fatalf("Connect: %s", err)
+		}
+		select {
+		case <-subscriptions:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the heartbeat subscription")
+		}
+		time.Sleep(50 * time.Millisecond)
+		if err := ws.Disconnect(); err != nil {
+			t.Fatalf("Disconnect: %s", err)
+		}
+	}
+	if len(subscriptions) != 0 || len(ws.Registry.List()) != 0 {
+		t.Errorf("expected a single unregistered heartbeat subscription per connection")
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	for message, expected := range map[string]bool{
+		"Failed to subscribe to authenticated feed": true,
+		"invalid api key":    true,
+		"Invalid product id": false,
+		"Failed to assign":   false,
+		"signal lost":        false,
+	} {
+		if isAuthError(message) != expected {
+			t.Errorf("expected %q to be an authentication error: %t", message, expected)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package derivatives
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/internal/helper"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// ErrAuthenticationTimeout is returned when the challenge does not arrive within [WebSocketBase.AuthenticateTimeout].
+var ErrAuthenticationTimeout = errors.New("authentication timed out")
+
+// authAttempt is an outstanding challenge request shared by concurrent callers of the authentication functions.
+type authAttempt struct {
+	done  chan struct{}
+	once  sync.Once
+	timer *time.Timer
+	err   error
+}
+
+// Authenticate submits a challenge request and blocks until the signed challenge is stored or [WebSocketBase.AuthenticateTimeout] elapses.
+//
+// The challenge is delivered by the read loop, so calling this within a [WebSocketBase] callback returns [ErrAuthenticationTimeout].
+// Use [WebSocketBase.AuthenticateAsync] there instead.
+func (b *WebSocketBase) Authenticate() error {
+	attempt, err := b.startAuth()
+	if err != nil {
+		return err
+	}
+	<-attempt.done
+	return attempt.err
+}
+
+// AuthenticateAsync submits a challenge request without waiting for the response.
+// OnAuthenticated is called once the signed challenge is stored and OnAuthFailed if it fails or times out.
+func (b *WebSocketBase) AuthenticateAsync() error {
+	_, err := b.startAuth()
+	return err
+}
+
+// startAuth submits a challenge request or returns the attempt in progress.
+func (b *WebSocketBase) startAuth() (*authAttempt, error) {
+	b.authMux.Lock()
+	defer b.authMux.Unlock()
+	if b.auth != nil {
+		return b.auth, nil
+	}
+	attempt := &authAttempt{done: make(chan struct{})}
+	b.auth = attempt
+	if err := b.WriteJSON(map[string]any{
+		"event":   "challenge",
+		"api_key": b.PublicKey,
+	}); err != nil {
+		b.auth = nil
+		return nil, fmt.Errorf("request challenge failed: %w", err)
+	}
+	if b.AuthenticateTimeout > 0 {
+		attempt.timer = time.AfterFunc(b.AuthenticateTimeout, func() {
+			b.finishAuth(attempt, fmt.Errorf("retrieve challenge: %w", ErrAuthenticationTimeout))
+		})
+	}
+	return attempt, nil
+}
+
+// finishAuth completes an attempt and calls OnAuthenticated or OnAuthFailed.
+func (b *WebSocketBase) finishAuth(attempt *authAttempt, err error) {
+	completed := false
+	attempt.once.Do(func() {
+		completed = true
+		b.authMux.Lock()
+		if attempt.timer != nil {
+			attempt.timer.Stop()
+		}
+		if b.auth == attempt {
+			b.auth = nil
+		}
+		b.authMux.Unlock()
+		attempt.err = err
+		close(attempt.done)
+	})
+	if !completed {
+		return
+	}
+	if err != nil {
+		b.OnAuthFailed.Call(err)
+		return
+	}
+	challenge, _ := b.CurrentChallenge()
+	b.OnAuthenticated.Call(challenge)
+}
+
+// handleChallenge signs the challenge of the attempt in progress.
+// An error or alert event fails the attempt and discards a reused challenge that the server rejected.
+func (b *WebSocketBase) handleChallenge(m *kraken.WebSocketMessage) {
+	data, err := m.Map()
+	if err != nil {
+		return
+	}
+	event, _ := data["event"].(string)
+	message, _ := data["message"].(string)
+	b.authMux.Lock()
+	attempt := b.auth
+	b.authMux.Unlock()
+	switch event {
+	case "challenge":
+		if attempt == nil {
+			return
+		}
+		if err := b.sign(message); err != nil {
+			b.finishAuth(attempt, err)
+			return
+		}
+		b.finishAuth(attempt, nil)
+	case "error", "alert":
+		if !isAuthError(message) {
+			return
+		}
+		if attempt != nil {
+			b.finishAuth(attempt, fmt.Errorf("retrieve challenge: %s", message))
+			return
+		}
+		if b.reused.CompareAndSwap(true, false) {
+			b.challengeMux.Lock()
+			b.Challenge = ""
+			b.Signature = ""
+			b.challengeMux.Unlock()
+			go b.restorePrivate()
+		}
+	}
+}
+
+// sign stores a challenge with its signature.
+func (b *WebSocketBase) sign(challenge string) error {
+	if challenge == "" {
+		return fmt.Errorf("retrieve challenge: empty message")
+	}
+	sha256Hash := sha256.New()
+	sha256Hash.Write([]byte(challenge))
+	signature, err := helper.Sign(b.PrivateKey, sha256Hash.Sum(nil))
+	if err != nil {
+		return fmt.Errorf("sign challenge failed: %s", err)
+	}
+	b.challengeMux.Lock()
+	b.Challenge = challenge
+	b.Signature = signature
+	b.challengeMux.Unlock()
+	return nil
+}
+
+// restorePrivate signs a new challenge and replays the private subscriptions after a reused challenge was rejected.
+func (b *WebSocketBase) restorePrivate() {
+	event := &kraken.RestoreEvent{}
+	var errs []error
+	if err := b.Authenticate(); err != nil {
+		errs = append(errs, err)
+	} else {
+		event.Authenticated = true
+		for _, subscription := range b.Registry.List() {
+			if !subscription.Private {
+				continue
+			}
+			if err := b.SendPrivate(subscription.Request); err != nil {
+				errs = append(errs, fmt.Errorf("resubscribe %s: %w", subscription.Channel, err))
+				continue
+			}
+			event.Subscriptions = append(event.Subscriptions, subscription)
+		}
+	}
+	event.Err = errors.Join(errs...)
+	b.OnRestored.Call(event)
+}
+
+// authErrors are the messages of the error and alert events that the server sends when a challenge request or a signed subscription is rejected.
+var authErrors = []string{
+	"Failed to subscribe to authenticated feed",
+	"Invalid API key",
+	"Invalid challenge",
+}
+
+// isAuthError returns whether the message of an error or alert event is one of the authentication failures.
+func isAuthError(message string) bool {
+	return slices.ContainsFunc(authErrors, func(authError string) bool {
+		return strings.EqualFold(strings.TrimSpace(message), authError)
+	})
+}
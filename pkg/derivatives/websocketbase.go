@@ -1,8 +1,11 @@
 package derivatives
 
 import (
-	"crypto/sha256"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/krakenfx/api-go/v2/internal/helper"
@@ -18,7 +21,24 @@ type WebSocketBase struct {
 	Challenge           string
 	Signature           string
 	OnAuthenticated     *callback.Manager[string]
+	OnAuthFailed        *callback.Manager[error]
 	*kraken.WebSocket
+
+	// Whether the signed challenge is reused after reconnecting instead of requesting a new one.
+	// A new challenge is signed automatically if the server rejects it.
+	ReuseChallenge bool
+
+	// Whether the subscriptions are replayed after reconnecting.
+	Resubscribe bool
+	Registry    *kraken.SubscriptionRegistry
+	OnRestored  *callback.Manager[*kraken.RestoreEvent]
+
+	state        *kraken.SubscriptionState
+	auth         *authAttempt
+	authMux      sync.Mutex
+	challengeMux sync.RWMutex
+	reused       atomic.Bool
+	heartbeat    atomic.Bool
 }
 
 // NewWebSocketBase constructs a [WebSocketBase] struct with default values.
@@ -27,81 +47,232 @@ func NewWebSocketBase() *WebSocketBase {
 		AuthenticateTimeout: 15 * time.Second,
 		WebSocket:           kraken.NewWebSocket(),
 		OnAuthenticated:     callback.NewManager[string](),
+		OnAuthFailed:        callback.NewManager[error](),
+		ReuseChallenge:      true,
+		Resubscribe:         true,
+		Registry:            kraken.NewSubscriptionRegistry(),
+		OnRestored:          callback.NewManager[*kraken.RestoreEvent](),
+		state:               kraken.NewSubscriptionState(),
 	}
 	b.URL = "wss://futures.kraken.com/ws/v1"
-	return b
-}
-
-// Authenticate submits a challenge request and retrieves the authentication fields.
-//
-// If contained within a [WebSocketBase] callback, this must be wrapped with a goroutine to prevent blocking.
-func (b *WebSocketBase) Authenticate() error {
-	if err := b.WriteJSON(map[string]any{
-		"event":   "challenge",
-		"api_key": b.PublicKey,
-	}); err != nil {
-		return fmt.Errorf("request challenge failed: %s", err)
-	}
-	var mainErr error
-	threadStarted := time.Now()
-	b.OnReceived.SleepUntilDisabled(func(e *callback.Event[*kraken.WebSocketMessage]) {
-		dataMap, err := e.Data.Map()
-		if err != nil {
-			e.Callback.Enabled = false
-			mainErr = err
-			return
-		}
-		if event, err := helper.Traverse[string](dataMap, "event"); err != nil || *event != "challenge" {
-			if time.Now().After(threadStarted.Add(b.AuthenticateTimeout)) {
-				e.Callback.Enabled = false
-				mainErr = fmt.Errorf("authentication timed out")
-				return
-			}
-			return
+	b.PingInterval = 30 * time.Second
+	b.StaleTimeout = 60 * time.Second
+	b.Heartbeat = b.subHeartbeat
+	b.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
+		b.handleChallenge(e.Data)
+		b.handleAck(e.Data)
+	})
+	// Clean up before the reconnect callback of [kraken.WebSocket] so that restored state is kept.
+	b.OnDisconnected.Priority(1, func(e *callback.Event[error]) {
+		b.state.Reset()
+		b.heartbeat.Store(false)
+		b.authMux.Lock()
+		attempt := b.auth
+		b.authMux.Unlock()
+		if attempt != nil {
+			b.finishAuth(attempt, fmt.Errorf("disconnected: %w", e.Data))
 		}
-		e.Callback.Enabled = false
-		message, err := helper.Traverse[string](dataMap, "message")
-		if err != nil {
-			mainErr = err
-			return
+	})
+	b.OnReconnected.Recurring(func(e *callback.Event[any]) {
+		if b.Resubscribe {
+			go b.Restore()
 		}
-		b.Challenge = *message
 	})
-	if mainErr != nil {
-		return fmt.Errorf("retrieve challenge: %w", mainErr)
+	return b
+}
+
+// subHeartbeat subscribes to the heartbeat feed once per connection so that an idle connection is not closed as stale.
+// The subscription is not stored in the registry since it is repeated on each new connection.
+func (b *WebSocketBase) subHeartbeat() error {
+	if b.heartbeat.Swap(true) {
+		return nil
 	}
-	sha256Hash := sha256.New()
-	sha256Hash.Write([]byte(b.Challenge))
-	signature, err := helper.Sign(b.PrivateKey, sha256Hash.Sum(nil))
-	if err != nil {
-		return fmt.Errorf("sign challenge failed: %s", err)
+	if err := b.WriteJSON(map[string]any{"event": "subscribe", "feed": "heartbeat"}); err != nil {
+		b.heartbeat.Store(false)
+		return err
 	}
-	b.Signature = signature
-	b.OnAuthenticated.Call(b.Challenge)
 	return nil
 }
 
 // SendPrivate sends a JSON-encoded map with the authentication fields included.
 func (b *WebSocketBase) SendPrivate(m map[string]any) error {
+	challenge, signature := b.CurrentChallenge()
 	return b.WriteJSON(helper.Maps(map[string]any{
 		"api_key":            b.PublicKey,
-		"original_challenge": b.Challenge,
-		"signed_challenge":   b.Signature,
+		"original_challenge": challenge,
+		"signed_challenge":   signature,
 	}, m))
 }
 
-// SubPublic submits a subscription request.
+// CurrentChallenge returns the last signed challenge with its signature or empty strings if the client is not authenticated.
+func (b *WebSocketBase) CurrentChallenge() (challenge string, signature string) {
+	b.challengeMux.RLock()
+	defer b.challengeMux.RUnlock()
+	return b.Challenge, b.Signature
+}
+
+// SubPublic submits a subscription request and stores it in the registry.
 func (b *WebSocket) SubPublic(feed string, options ...map[string]any) error {
-	return b.WriteJSON(helper.Maps(map[string]any{
+	request := helper.Maps(map[string]any{
 		"event": "subscribe",
 		"feed":  feed,
-	}, options...))
+	}, options...)
+	if err := b.WriteJSON(request); err != nil {
+		return err
+	}
+	b.register(feed, false, request)
+	return nil
 }
 
-// SubPrivate submits a subscription request with the authentication fields included.
+// SubPrivate submits a subscription request with the authentication fields included and stores it in the registry.
 func (b *WebSocket) SubPrivate(feed string, options ...map[string]any) error {
-	return b.SendPrivate(helper.Maps(map[string]any{
+	request := helper.Maps(map[string]any{
 		"event": "subscribe",
 		"feed":  feed,
-	}, options...))
+	}, options...)
+	if err := b.SendPrivate(request); err != nil {
+		return err
+	}
+	b.register(feed, true, request)
+	return nil
+}
+
+// UnsubPublic submits an unsubscribe request and removes the matching subscriptions from the registry.
+func (b *WebSocket) UnsubPublic(feed string, options ...map[string]any) error {
+	request := helper.Maps(map[string]any{
+		"event": "unsubscribe",
+		"feed":  feed,
+	}, options...)
+	if err := b.WriteJSON(request); err != nil {
+		return err
+	}
+	b.unregister(feed, request)
+	return nil
+}
+
+// UnsubPrivate submits an unsubscribe request with the authentication fields included and removes the matching subscriptions from the registry.
+func (b *WebSocket) UnsubPrivate(feed string, options ...map[string]any) error {
+	request := helper.Maps(map[string]any{
+		"event": "unsubscribe",
+		"feed":  feed,
+	}, options...)
+	if err := b.SendPrivate(request); err != nil {
+		return err
+	}
+	b.unregister(feed, request)
+	return nil
+}
+
+// requestProductIDs returns the product IDs of a request.
+func requestProductIDs(request map[string]any) []string {
+	if request["product_ids"] == nil {
+		return nil
+	}
+	productIDs, _ := helper.StringSlice(request["product_ids"])
+	return productIDs
+}
+
+// register stores a subscription request in the registry with one entry per product ID.
+func (b *WebSocketBase) register(feed string, private bool, request map[string]any) {
+	productIDs := requestProductIDs(request)
+	if len(productIDs) == 0 {
+		b.Registry.Add(&kraken.Subscription{Channel: feed, Private: private, Request: request})
+		return
+	}
+	for _, productID := range productIDs {
+		b.Registry.Add(&kraken.Subscription{
+			Channel: feed,
+			Symbol:  productID,
+			Private: private,
+			Request: helper.Maps(request, map[string]any{
+				"product_ids": []string{productID},
+			}),
+		})
+	}
+}
+
+// unregister removes the subscriptions of a feed matching the product IDs of an unsubscribe request.
+func (b *WebSocketBase) unregister(feed string, request map[string]any) {
+	productIDs := requestProductIDs(request)
+	b.Registry.RemoveFunc(func(s *kraken.Subscription) bool {
+		return s.Channel == feed && (len(productIDs) == 0 || slices.Contains(productIDs, s.Symbol))
+	})
+}
+
+// handleAck records the subscription state from the `subscribed` and `unsubscribed` events.
+func (b *WebSocketBase) handleAck(m *kraken.WebSocketMessage) {
+	data, err := m.Map()
+	if err != nil {
+		return
+	}
+	event, _ := data["event"].(string)
+	if event != "subscribed" && event != "unsubscribed" {
+		return
+	}
+	feed, _ := data["feed"].(string)
+	productIDs := requestProductIDs(data)
+	if len(productIDs) == 0 {
+		productIDs = []string{""}
+	}
+	for _, productID := range productIDs {
+		b.state.Set(feed, productID, event == "subscribed")
+	}
+}
+
+// Subscriptions returns the product IDs by feed whose subscriptions were confirmed by the server.
+// Feeds without product IDs, such as fills, contain an empty product ID.
+func (b *WebSocketBase) Subscriptions() map[string][]string {
+	return b.state.Map()
+}
+
+// IsSubscribed returns whether the server confirmed the subscription of a product ID on a feed.
+// Use an empty product ID for feeds without product IDs.
+func (b *WebSocketBase) IsSubscribed(feed string, productID string) bool {
+	return b.state.IsSubscribed(feed, productID)
+}
+
+// Restore signs a new challenge if the client was authenticated and replays the stored subscriptions.
+// The previous challenge is reused instead if ReuseChallenge is enabled.
+// Private subscriptions are skipped if authentication fails.
+//
+// It is called after reconnecting if Resubscribe is enabled. It blocks until the challenge is received and must not be called within a callback of the read loop.
+func (b *WebSocketBase) Restore() {
+	event := &kraken.RestoreEvent{}
+	var errs []error
+	subscriptions := b.Registry.List()
+	challenge, signature := b.CurrentChallenge()
+	private := challenge != ""
+	for _, subscription := range subscriptions {
+		private = private || subscription.Private
+	}
+	switch {
+	case !private:
+	case b.ReuseChallenge && signature != "":
+		b.reused.Store(true)
+		event.Authenticated = true
+	default:
+		if err := b.Authenticate(); err != nil {
+			errs = append(errs, err)
+		} else {
+			event.Authenticated = true
+		}
+	}
+	for _, subscription := range subscriptions {
+		var err error
+		switch {
+		case !subscription.Private:
+			err = b.WriteJSON(subscription.Request)
+		case event.Authenticated:
+			err = b.SendPrivate(subscription.Request)
+		default:
+			err = fmt.Errorf("not authenticated")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resubscribe %s: %w", subscription.Channel, err))
+			continue
+		}
+		event.Subscriptions = append(event.Subscriptions, subscription)
+	}
+	event.Err = errors.Join(errs...)
+	b.OnRestored.Call(event)
 }
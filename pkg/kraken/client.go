@@ -0,0 +1,107 @@
+package kraken
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// Protocol selects the HTTP version used by a client.
+type Protocol uint8
+
+const (
+	// Negotiate HTTP/2 with ALPN over TLS and fall back to HTTP/1.1, including for plain http:// URLs.
+	ProtocolAuto Protocol = iota
+	// Use HTTP/1.1 only.
+	ProtocolHTTP1
+	// Use HTTP/2 only. Proxies are not supported.
+	ProtocolHTTP2
+)
+
+// ClientOptions contains the parameters for [NewClient].
+// Zero values are replaced with defaults.
+type ClientOptions struct {
+	Protocol Protocol
+
+	// Proxy of the transport. Defaults to [http.ProxyFromEnvironment].
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// TLS configuration for custom root certificates or client certificates.
+	TLSConfig *tls.Config
+
+	// Time limit for the whole request including reading the body. Defaults to no limit.
+	Timeout time.Duration
+
+	DialTimeout         time.Duration
+	KeepAlive           time.Duration
+	TLSHandshakeTimeout time.Duration
+	IdleConnTimeout     time.Duration
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+}
+
+// DefaultClient is the shared [http.Client] used by requests without an executor.
+var DefaultClient = NewClient(ClientOptions{})
+
+// NewClient constructs an [http.Client] with a transport configured by [ClientOptions].
+// The client is safe for concurrent use and should be reused to benefit from connection pooling.
+func NewClient(opts ClientOptions) *http.Client {
+	if opts.Proxy == nil {
+		opts.Proxy = http.ProxyFromEnvironment
+	}
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = 30 * time.Second
+	}
+	if opts.KeepAlive == 0 {
+		opts.KeepAlive = 30 * time.Second
+	}
+	if opts.TLSHandshakeTimeout == 0 {
+		opts.TLSHandshakeTimeout = 10 * time.Second
+	}
+	if opts.IdleConnTimeout == 0 {
+		opts.IdleConnTimeout = 90 * time.Second
+	}
+	if opts.MaxIdleConns == 0 {
+		opts.MaxIdleConns = 100
+	}
+	if opts.MaxIdleConnsPerHost == 0 {
+		opts.MaxIdleConnsPerHost = 10
+	}
+	dialer := &net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: opts.KeepAlive,
+	}
+	var transport http.RoundTripper
+	switch opts.Protocol {
+	case ProtocolHTTP2:
+		transport = &http2.Transport{
+			TLSClientConfig: opts.TLSConfig,
+			IdleConnTimeout: opts.IdleConnTimeout,
+		}
+	default:
+		httpTransport := &http.Transport{
+			Proxy:               opts.Proxy,
+			DialContext:         dialer.DialContext,
+			TLSClientConfig:     opts.TLSConfig,
+			TLSHandshakeTimeout: opts.TLSHandshakeTimeout,
+			IdleConnTimeout:     opts.IdleConnTimeout,
+			MaxIdleConns:        opts.MaxIdleConns,
+			MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     opts.MaxConnsPerHost,
+			ForceAttemptHTTP2:   opts.Protocol == ProtocolAuto,
+		}
+		if opts.Protocol == ProtocolHTTP1 {
+			httpTransport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		}
+		transport = httpTransport
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   opts.Timeout,
+	}
+}
@@ -0,0 +1,37 @@
+package kraken
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClientProtocol(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+	tests := []struct {
+		protocol Protocol
+		major    int
+	}{
+		{ProtocolAuto, 2},
+		{ProtocolHTTP1, 1},
+		{ProtocolHTTP2, 2},
+	}
+	for _, test := range tests {
+		client := NewClient(ClientOptions{Protocol: test.protocol, TLSConfig: tlsConfig.Clone()})
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Errorf("protocol %d: %s", test.protocol, err)
+			continue
+		}
+		_ = resp.Body.Close()
+		if resp.ProtoMajor != test.major {
+			t.Errorf("protocol %d: expected HTTP/%d, got %s", test.protocol, test.major, resp.Proto)
+		}
+	}
+}
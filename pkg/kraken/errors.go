@@ -0,0 +1,241 @@
+package kraken
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorSeverity indicates whether an API message is an error or a warning.
+type ErrorSeverity string
+
+const (
+	SeverityError   ErrorSeverity = "E"
+	SeverityWarning ErrorSeverity = "W"
+)
+
+// ErrorCategory groups API errors by the subsystem that raised them.
+type ErrorCategory string
+
+const (
+	CategoryGeneral  ErrorCategory = "General"
+	CategoryAPI      ErrorCategory = "API"
+	CategoryQuery    ErrorCategory = "Query"
+	CategoryOrder    ErrorCategory = "Order"
+	CategoryTrade    ErrorCategory = "Trade"
+	CategoryFunding  ErrorCategory = "Funding"
+	CategoryService  ErrorCategory = "Service"
+	CategorySession  ErrorCategory = "Session"
+	CategoryDatabase ErrorCategory = "Database"
+	CategoryAccount  ErrorCategory = "Account"
+	CategoryUnknown  ErrorCategory = "Unknown"
+)
+
+// Error is an error reported by the exchange in the response body.
+//
+// Errors are matched with [errors.Is] by their code.
+// Futures errors also match their spot equivalent, e.g. "nonceBelowThreshold" matches [ErrInvalidNonce].
+type Error struct {
+	// Code identifying the error, e.g. "EOrder:Insufficient funds" or "apiLimitExceeded".
+	Code string `json:"code,omitempty"`
+
+	// Additional information appended to the code by the exchange.
+	Detail string `json:"detail,omitempty"`
+
+	Category  ErrorCategory `json:"category,omitempty"`
+	Severity  ErrorSeverity `json:"severity,omitempty"`
+	Retryable bool          `json:"retryable,omitempty"`
+
+	parent *Error
+}
+
+// Error implements the error interface and returns the message as reported by the exchange.
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return e.Code
+	}
+	return e.Code + ":" + e.Detail
+}
+
+// Is reports whether target is an [Error] with the same code as e or one of its equivalents.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	for cursor := e; cursor != nil; cursor = cursor.parent {
+		if cursor.Code == t.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryable reports whether err contains an [Error] that may succeed if the request is submitted again.
+func IsRetryable(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Retryable
+}
+
+func newError(code string, category ErrorCategory, retryable bool, parent *Error) *Error {
+	return &Error{
+		Code:      code,
+		Category:  category,
+		Severity:  SeverityError,
+		Retryable: retryable,
+		parent:    parent,
+	}
+}
+
+// Spot errors.
+//
+// https://docs.kraken.com/api/docs/guides/spot-errors
+var (
+	ErrInvalidArguments  = newError("EGeneral:Invalid arguments", CategoryGeneral, false, nil)
+	ErrIndexUnavailable  = newError("EGeneral:Invalid arguments:Index unavailable", CategoryGeneral, false, ErrInvalidArguments)
+	ErrTemporaryLockout  = newError("EGeneral:Temporary lockout", CategoryGeneral, false, nil)
+	ErrPermissionDenied  = newError("EGeneral:Permission denied", CategoryGeneral, false, nil)
+	ErrInternalError     = newError("EGeneral:Internal error", CategoryGeneral, true, nil)
+	ErrTooManyRequests   = newError("EGeneral:Too many requests", CategoryGeneral, true, nil)
+	ErrUnknownMethod     = newError("EGeneral:Unknown method", CategoryGeneral, false, nil)
+	ErrInvalidKey        = newError("EAPI:Invalid key", CategoryAPI, false, nil)
+	ErrInvalidSignature  = newError("EAPI:Invalid signature", CategoryAPI, false, nil)
+	ErrInvalidNonce      = newError("EAPI:Invalid nonce", CategoryAPI, true, nil)
+	ErrRateLimitExceeded = newError("EAPI:Rate limit exceeded", CategoryAPI, true, nil)
+	ErrFeatureDisabled   = newError("EAPI:Feature disabled", CategoryAPI, false, nil)
+	ErrBadRequest        = newError("EAPI:Bad request", CategoryAPI, false, nil)
+	ErrInvalidToken      = newError("EAPI:Invalid token", CategoryAPI, false, nil)
+	ErrUnknownAssetPair  = newError("EQuery:Unknown asset pair", CategoryQuery, false, nil)
+	ErrUnknownAsset      = newError("EQuery:Unknown asset", CategoryQuery, false, nil)
+
+	ErrCannotOpenOpposingPosition = newError("EOrder:Cannot open opposing position", CategoryOrder, false, nil)
+	ErrCannotOpenPosition         = newError("EOrder:Cannot open position", CategoryOrder, false, nil)
+	ErrMarginAllowanceExceeded    = newError("EOrder:Margin allowance exceeded", CategoryOrder, false, nil)
+	ErrMarginLevelTooLow          = newError("EOrder:Margin level too low", CategoryOrder, false, nil)
+	ErrMarginPositionSizeExceeded = newError("EOrder:Margin position size exceeded", CategoryOrder, false, nil)
+	ErrInsufficientMargin         = newError("EOrder:Insufficient margin", CategoryOrder, false, nil)
+	ErrInsufficientFunds          = newError("EOrder:Insufficient funds", CategoryOrder, false, nil)
+	ErrOrderMinimumNotMet         = newError("EOrder:Order minimum not met", CategoryOrder, false, nil)
+	ErrCostMinimumNotMet          = newError("EOrder:Cost minimum not met", CategoryOrder, false, nil)
+	ErrTickSizeCheckFailed        = newError("EOrder:Tick size check failed", CategoryOrder, false, nil)
+	ErrOrdersLimitExceeded        = newError("EOrder:Orders limit exceeded", CategoryOrder, false, nil)
+	ErrOrderRateLimitExceeded     = newError("EOrder:Rate limit exceeded", CategoryOrder, true, nil)
+	ErrDomainRateLimitExceeded    = newError("EOrder:Domain rate limit exceeded", CategoryOrder, true, nil)
+	ErrPositionsLimitExceeded     = newError("EOrder:Positions limit exceeded", CategoryOrder, false, nil)
+	ErrUnknownPosition            = newError("EOrder:Unknown position", CategoryOrder, false, nil)
+	ErrUnknownOrder               = newError("EOrder:Unknown order", CategoryOrder, false, nil)
+	ErrInvalidPrice               = newError("EOrder:Invalid price", CategoryOrder, false, nil)
+
+	ErrUnknownWithdrawKey   = newError("EFunding:Unknown withdraw key", CategoryFunding, false, nil)
+	ErrFundingInvalidAmount = newError("EFunding:Invalid amount", CategoryFunding, false, nil)
+	ErrUnknownReferenceID   = newError("EFunding:Unknown reference id", CategoryFunding, false, nil)
+	ErrTooManyAddresses     = newError("EFunding:Too many addresses", CategoryFunding, false, nil)
+	ErrMaxFeeExceeded       = newError("EFunding:Max fee exceeded", CategoryFunding, false, nil)
+
+	ErrServiceUnavailable = newError("EService:Unavailable", CategoryService, true, nil)
+	ErrServiceBusy        = newError("EService:Busy", CategoryService, true, nil)
+	ErrMarketCancelOnly   = newError("EService:Market in cancel_only mode", CategoryService, false, nil)
+	ErrMarketPostOnly     = newError("EService:Market in post_only mode", CategoryService, false, nil)
+	ErrMarketLimitOnly    = newError("EService:Market in limit_only mode", CategoryService, false, nil)
+	ErrDeadlineElapsed    = newError("EService:Deadline elapsed", CategoryService, false, nil)
+
+	ErrInvalidSession = newError("ESession:Invalid session", CategorySession, false, nil)
+	ErrDatabaseError  = newError("EDatabase:Internal error", CategoryDatabase, true, nil)
+)
+
+// Futures errors.
+//
+// https://docs.kraken.com/api/docs/guides/futures-rest#errors
+var (
+	ErrAccountInactive         = newError("accountInactive", CategoryAccount, false, nil)
+	ErrAPILimitExceeded        = newError("apiLimitExceeded", CategoryAPI, true, ErrRateLimitExceeded)
+	ErrAuthenticationError     = newError("authenticationError", CategoryAPI, false, nil)
+	ErrInvalidAccount          = newError("invalidAccount", CategoryAccount, false, nil)
+	ErrInvalidAmount           = newError("invalidAmount", CategoryGeneral, false, ErrInvalidArguments)
+	ErrInvalidArgument         = newError("invalidArgument", CategoryGeneral, false, ErrInvalidArguments)
+	ErrInvalidUnit             = newError("invalidUnit", CategoryGeneral, false, ErrInvalidArguments)
+	ErrRequiredArgumentMissing = newError("requiredArgumentMissing", CategoryGeneral, false, ErrInvalidArguments)
+	ErrJSONParse               = newError("Json Parse Error", CategoryAPI, false, ErrBadRequest)
+	ErrMarketUnavailable       = newError("marketUnavailable", CategoryService, false, nil)
+	ErrNonceBelowThreshold     = newError("nonceBelowThreshold", CategoryAPI, true, ErrInvalidNonce)
+	ErrNonceDuplicate          = newError("nonceDuplicate", CategoryAPI, true, ErrInvalidNonce)
+	ErrNotFound                = newError("notFound", CategoryQuery, false, nil)
+	ErrUnknown                 = newError("unknownError", CategoryUnknown, false, nil)
+
+	errDerivativesInsufficientFunds = newError("insufficientFunds", CategoryOrder, false, ErrInsufficientFunds)
+	errDerivativesServerError       = newError("Server Error", CategoryService, true, ErrInternalError)
+	errDerivativesUnavailable       = newError("Unavailable", CategoryService, true, ErrServiceUnavailable)
+)
+
+var spotErrors = indexErrors(
+	ErrInvalidArguments, ErrIndexUnavailable, ErrTemporaryLockout, ErrPermissionDenied, ErrInternalError,
+	ErrTooManyRequests, ErrUnknownMethod, ErrInvalidKey, ErrInvalidSignature, ErrInvalidNonce,
+	ErrRateLimitExceeded, ErrFeatureDisabled, ErrBadRequest, ErrInvalidToken, ErrUnknownAssetPair, ErrUnknownAsset,
+	ErrCannotOpenOpposingPosition, ErrCannotOpenPosition, ErrMarginAllowanceExceeded, ErrMarginLevelTooLow,
+	ErrMarginPositionSizeExceeded, ErrInsufficientMargin, ErrInsufficientFunds, ErrOrderMinimumNotMet,
+	ErrCostMinimumNotMet, ErrTickSizeCheckFailed, ErrOrdersLimitExceeded, ErrOrderRateLimitExceeded,
+	ErrDomainRateLimitExceeded, ErrPositionsLimitExceeded, ErrUnknownPosition, ErrUnknownOrder, ErrInvalidPrice,
+	ErrUnknownWithdrawKey, ErrFundingInvalidAmount, ErrUnknownReferenceID, ErrTooManyAddresses, ErrMaxFeeExceeded,
+	ErrServiceUnavailable, ErrServiceBusy, ErrMarketCancelOnly, ErrMarketPostOnly, ErrMarketLimitOnly,
+	ErrDeadlineElapsed, ErrInvalidSession, ErrDatabaseError,
+)
+
+var derivativesErrors = indexErrors(
+	ErrAccountInactive, ErrAPILimitExceeded, ErrAuthenticationError, ErrInvalidAccount, ErrInvalidAmount,
+	ErrInvalidArgument, ErrInvalidUnit, ErrRequiredArgumentMissing, ErrJSONParse, ErrMarketUnavailable,
+	ErrNonceBelowThreshold, ErrNonceDuplicate, ErrNotFound, ErrUnknown,
+	errDerivativesInsufficientFunds, errDerivativesServerError, errDerivativesUnavailable,
+)
+
+func indexErrors(errs ...*Error) map[string]*Error {
+	m := make(map[string]*Error, len(errs))
+	for _, err := range errs {
+		m[err.Code] = err
+	}
+	return m
+}
+
+// ParseSpotError converts a message from the `error` array of a Spot API response into an [Error].
+//
+// Messages are formatted as "<severity><category>:<message>[:<detail>]".
+// The longest known code is matched and the remainder is stored in [Error.Detail].
+func ParseSpotError(s string) *Error {
+	parts := strings.Split(s, ":")
+	for i := len(parts); i > 0; i-- {
+		code := strings.Join(parts[:i], ":")
+		if known, ok := spotErrors[code]; ok {
+			result := *known
+			result.Detail = strings.Join(parts[i:], ":")
+			result.parent = known
+			return &result
+		}
+	}
+	result := &Error{
+		Code:     s,
+		Category: CategoryUnknown,
+		Severity: SeverityError,
+	}
+	if len(parts) > 1 && len(parts[0]) > 1 {
+		result.Code = parts[0] + ":" + parts[1]
+		result.Detail = strings.Join(parts[2:], ":")
+		result.Severity = ErrorSeverity(parts[0][:1])
+		result.Category = ErrorCategory(parts[0][1:])
+	}
+	return result
+}
+
+// ParseDerivativesError converts the `error` field of a Futures API response into an [Error].
+func ParseDerivativesError(s string) *Error {
+	if known, ok := derivativesErrors[s]; ok {
+		result := *known
+		result.parent = known
+		return &result
+	}
+	return &Error{
+		Code:     s,
+		Category: CategoryUnknown,
+		Severity: SeverityError,
+	}
+}
@@ -0,0 +1,45 @@
+package kraken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSpotError(t *testing.T) {
+	err := ParseSpotError("EOrder:Insufficient funds")
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("errors.Is(%s, ErrInsufficientFunds) = false", err)
+	}
+	if err.Category != CategoryOrder || err.Severity != SeverityError {
+		t.Errorf("unexpected category %s or severity %s", err.Category, err.Severity)
+	}
+	err = ParseSpotError("EGeneral:Invalid arguments:volume")
+	if !errors.Is(err, ErrInvalidArguments) || err.Detail != "volume" {
+		t.Errorf("ParseSpotError(EGeneral:Invalid arguments:volume) = %+v", err)
+	}
+	if err.Error() != "EGeneral:Invalid arguments:volume" {
+		t.Errorf("Error() != EGeneral:Invalid arguments:volume, got %s", err)
+	}
+	if err = ParseSpotError("EGeneral:Invalid arguments:Index unavailable"); !errors.Is(err, ErrIndexUnavailable) || !errors.Is(err, ErrInvalidArguments) {
+		t.Errorf("ParseSpotError(EGeneral:Invalid arguments:Index unavailable) = %+v", err)
+	}
+	if err = ParseSpotError("WNew:Something"); err.Severity != SeverityWarning || err.Category != "New" {
+		t.Errorf("ParseSpotError(WNew:Something) = %+v", err)
+	}
+	if !IsRetryable(errors.Join(ParseSpotError("EService:Busy"))) {
+		t.Errorf("IsRetryable(EService:Busy) = false")
+	}
+}
+
+func TestParseDerivativesError(t *testing.T) {
+	err := ParseDerivativesError("nonceBelowThreshold")
+	if !errors.Is(err, ErrNonceBelowThreshold) || !errors.Is(err, ErrInvalidNonce) {
+		t.Errorf("ParseDerivativesError(nonceBelowThreshold) = %+v", err)
+	}
+	if errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("errors.Is(nonceBelowThreshold, ErrInsufficientFunds) = true")
+	}
+	if err := ParseDerivativesError("apiLimitExceeded"); !errors.Is(err, ErrRateLimitExceeded) || !err.Retryable {
+		t.Errorf("ParseDerivativesError(apiLimitExceeded) = %+v", err)
+	}
+}
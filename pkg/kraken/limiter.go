@@ -0,0 +1,122 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by a client-side limiter in [FailFastMode] when a request would exceed the limit.
+var ErrRateLimited = errors.New("client-side rate limit reached")
+
+// Whether a limiter should wait for capacity or return an error immediately.
+type LimitMode uint8
+
+const (
+	BlockMode LimitMode = iota
+	FailFastMode
+)
+
+// DecayCounter models the exchange rate limit counters which increase with each request and decay linearly over time.
+type DecayCounter struct {
+	max       float64
+	decayRate float64
+	value     float64
+	updated   time.Time
+	mux       sync.Mutex
+}
+
+// NewDecayCounter constructs a [DecayCounter] with a maximum value and a decay rate per second.
+func NewDecayCounter(max float64, decayRate float64) *DecayCounter {
+	return &DecayCounter{
+		max:       max,
+		decayRate: decayRate,
+		updated:   time.Now(),
+	}
+}
+
+// decay reduces the value by the time elapsed since the last update. Must be called with the lock held.
+func (c *DecayCounter) decay() {
+	now := time.Now()
+	c.value = math.Max(0, c.value-now.Sub(c.updated).Seconds()*c.decayRate)
+	c.updated = now
+}
+
+// Value returns the current value of the counter.
+func (c *DecayCounter) Value() float64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.decay()
+	return c.value
+}
+
+// Add increases the counter by cost regardless of the maximum.
+func (c *DecayCounter) Add(cost float64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.decay()
+	c.value += cost
+}
+
+// TryAdd increases the counter by cost if it stays within the maximum.
+// Otherwise, it returns the duration until there is enough capacity.
+//
+// A cost greater than the maximum is accepted once the counter has fully decayed.
+func (c *DecayCounter) TryAdd(cost float64) (time.Duration, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.decay()
+	excess := c.value + cost - c.max
+	if excess <= 0 || c.value == 0 {
+		c.value += cost
+		return 0, true
+	}
+	if c.decayRate <= 0 {
+		return time.Duration(math.MaxInt64), false
+	}
+	excess = math.Min(excess, c.value)
+	return time.Duration(excess / c.decayRate * float64(time.Second)), false
+}
+
+// Reserve increases the counter by cost. In [BlockMode], it waits until there is capacity or ctx is done.
+// In [FailFastMode], it returns [ErrRateLimited] if there is no capacity.
+func (c *DecayCounter) Reserve(ctx context.Context, cost float64, mode LimitMode) error {
+	for {
+		wait, ok := c.TryAdd(cost)
+		if ok {
+			return nil
+		}
+		if mode == FailFastMode {
+			return fmt.Errorf("%w: cost %g exceeds remaining capacity, retry in %s", ErrRateLimited, cost, wait)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// DecayCounterState is a snapshot of a [DecayCounter].
+type DecayCounterState struct {
+	Value     float64 `json:"value"`
+	Max       float64 `json:"max"`
+	DecayRate float64 `json:"decayRate"`
+}
+
+// State returns a snapshot of the counter.
+func (c *DecayCounter) State() DecayCounterState {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.decay()
+	return DecayCounterState{
+		Value:     c.value,
+		Max:       c.max,
+		DecayRate: c.decayRate,
+	}
+}
@@ -0,0 +1,31 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecayCounter(t *testing.T) {
+	counter := NewDecayCounter(2, 100)
+	if err := counter.Reserve(context.Background(), 2, FailFastMode); err != nil {
+		t.Fatalf("Reserve(2): %s", err)
+	}
+	if err := counter.Reserve(context.Background(), 1, FailFastMode); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Reserve(1) over capacity: expected ErrRateLimited, got %v", err)
+	}
+	started := time.Now()
+	if err := counter.Reserve(context.Background(), 1, BlockMode); err != nil {
+		t.Errorf("Reserve(1) in BlockMode: %s", err)
+	}
+	if elapsed := time.Since(started); elapsed < 5*time.Millisecond {
+		t.Errorf("Reserve(1) in BlockMode returned after %s, expected a wait", elapsed)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	counter.Add(10)
+	if err := counter.Reserve(ctx, 1, BlockMode); !errors.Is(err, context.Canceled) {
+		t.Errorf("Reserve with cancelled context: expected context.Canceled, got %v", err)
+	}
+}
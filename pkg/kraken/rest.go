@@ -2,6 +2,7 @@ package kraken
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,7 +11,6 @@ import (
 	"strings"
 
 	"github.com/krakenfx/api-go/v2/internal/helper"
-	"golang.org/x/net/http2"
 )
 
 // Request is a wrapper around [http.Request] to assist with internal functions.
@@ -21,7 +21,12 @@ type Request struct {
 
 // NewRequest initializes a [Request] object with default values.
 func NewRequest() *Request {
-	return &Request{
+	return NewRequestContext(context.Background())
+}
+
+// NewRequestContext initializes a [Request] object with default values and the given context.
+func NewRequestContext(ctx context.Context) *Request {
+	request := &Request{
 		Request: &http.Request{
 			Method: "GET",
 			Header: http.Header{
@@ -31,14 +36,15 @@ func NewRequest() *Request {
 			Body:    http.NoBody,
 			GetBody: func() (io.ReadCloser, error) { return http.NoBody, nil },
 		},
-		Executor: (&http.Client{
-			Transport: &http2.Transport{},
-		}).Do,
+		Executor: DefaultClient.Do,
 	}
+	request.Request = request.WithContext(ctx)
+	return request
 }
 
 // RequestOptions contains the parameters for [NewRequestWithOptions].
 type RequestOptions struct {
+	Context     context.Context
 	Method      string
 	URL         string
 	Headers     map[string]any
@@ -60,7 +66,11 @@ const (
 
 // NewRequestWithOptions constructs a new [Request] with [RequestOptions].
 func NewRequestWithOptions(opts RequestOptions) (request *Request, err error) {
-	request = NewRequest()
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	request = NewRequestContext(ctx)
 	if err := request.SetURL(opts.URL); err != nil {
 		return request, fmt.Errorf("set URL: %w", err)
 	}
@@ -129,6 +139,12 @@ func (r *Request) Do() (resp *Response, err error) {
 	return result, nil
 }
 
+// DoContext replaces the request context with ctx and submits the request.
+func (r *Request) DoContext(ctx context.Context) (*Response, error) {
+	r.Request = r.WithContext(ctx)
+	return r.Do()
+}
+
 // MustDo submits the request and returns a [Response]. Panics on error.
 func (r *Request) MustDo() *Response {
 	return helper.Must(r.Do())
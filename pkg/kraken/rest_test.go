@@ -0,0 +1,31 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, err := NewRequestWithOptions(RequestOptions{
+		Context: ctx,
+		URL:     server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewRequestWithOptions: %s", err)
+	}
+	if _, err := request.Do(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() with cancelled context: expected context.Canceled, got %v", err)
+	}
+	if _, err := request.DoContext(context.Background()); err != nil {
+		t.Errorf("DoContext(context.Background()): %s", err)
+	}
+}
@@ -0,0 +1,183 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+// Outcome classifies a failed attempt to decide whether it can be retried.
+type Outcome uint8
+
+const (
+	// The failure is permanent and the request should not be retried.
+	OutcomePermanent Outcome = iota
+	// The request was rejected before being processed and is safe to resubmit.
+	OutcomeRejected
+	// The request may have been processed. Only idempotent requests should be resubmitted.
+	OutcomeUnknown
+)
+
+// ClassifyFunction decides the [Outcome] of an attempt from its response and error.
+type ClassifyFunction func(resp *Response, err error) Outcome
+
+// Classify implements [ClassifyFunction]:
+//
+// - Rate limits, invalid nonces, unavailable or busy services, HTTP 429, and failed dials are [OutcomeRejected].
+//
+// - Other network errors, HTTP 5xx, and internal errors are [OutcomeUnknown].
+//
+// - Everything else, including cancelled contexts, is [OutcomePermanent].
+func Classify(resp *Response, err error) Outcome {
+	if err == nil {
+		return OutcomePermanent
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return OutcomePermanent
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case !apiErr.Retryable:
+			return OutcomePermanent
+		case errors.Is(apiErr, ErrInternalError), errors.Is(apiErr, ErrDatabaseError):
+			return OutcomeUnknown
+		default:
+			return OutcomeRejected
+		}
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return OutcomeRejected
+	}
+	if resp != nil && resp.Response != nil {
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return OutcomeRejected
+		case resp.StatusCode >= 500:
+			return OutcomeUnknown
+		}
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return OutcomeRejected
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return OutcomeUnknown
+	}
+	return OutcomePermanent
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	Attempt int           `json:"attempt,omitempty"`
+	Delay   time.Duration `json:"delay,omitempty"`
+	Outcome Outcome       `json:"outcome,omitempty"`
+	Err     error         `json:"-"`
+}
+
+// RetryPolicy retries failed calls with jittered exponential backoff.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one.
+	MaxAttempts int
+
+	// Delay before the first retry, doubled for each subsequent retry.
+	BaseDelay time.Duration
+
+	// Upper bound of the delay between attempts.
+	MaxDelay time.Duration
+
+	// Fraction of the delay that is randomized, between 0 and 1.
+	Jitter float64
+
+	Classify ClassifyFunction
+
+	OnRetry *callback.Manager[*RetryEvent]
+}
+
+// NewRetryPolicy constructs a [RetryPolicy] with default values.
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.5,
+		Classify:    Classify,
+		OnRetry:     callback.NewManager[*RetryEvent](),
+	}
+}
+
+// RetryPolicyProvider is implemented by clients with a [RetryPolicy].
+type RetryPolicyProvider interface {
+	GetRetryPolicy() *RetryPolicy
+}
+
+// Backoff returns the delay before the given retry, starting at 1.
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 {
+		delay = math.Min(delay, float64(p.MaxDelay))
+	}
+	jitter := math.Min(math.Max(p.Jitter, 0), 1)
+	delay -= delay * jitter * rand.Float64()
+	return time.Duration(delay)
+}
+
+// Do calls attempt until it succeeds, the failure cannot be retried, or the attempts are exhausted.
+//
+// Failures with [OutcomeUnknown] are only retried if idempotent is true.
+// Each call of attempt must construct and sign a new request so that a fresh nonce is used.
+func (p *RetryPolicy) Do(ctx context.Context, idempotent bool, attempt func() (*Response, error)) error {
+	classify := p.Classify
+	if classify == nil {
+		classify = Classify
+	}
+	for i := 1; ; i++ {
+		resp, err := attempt()
+		if err == nil || i >= p.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		outcome := classify(resp, err)
+		if outcome == OutcomePermanent || (outcome == OutcomeUnknown && !idempotent) {
+			return err
+		}
+		delay := p.Backoff(i)
+		if after := retryAfter(resp); after > delay {
+			delay = after
+		}
+		if p.OnRetry != nil {
+			p.OnRetry.Call(&RetryEvent{
+				Attempt: i,
+				Delay:   delay,
+				Outcome: outcome,
+				Err:     err,
+			})
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryAfter parses the Retry-After header in seconds.
+func retryAfter(resp *Response) time.Duration {
+	if resp == nil || resp.Response == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,50 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := NewRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	var attempts int
+	err := policy.Do(context.Background(), false, func() (*Response, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, ParseSpotError("EAPI:Invalid nonce")
+		}
+		return nil, nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Do() with rejected failures: err = %v, attempts = %d", err, attempts)
+	}
+	attempts = 0
+	serverError := &Response{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+	err = policy.Do(context.Background(), false, func() (*Response, error) {
+		attempts++
+		return serverError, errors.New("bad gateway")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Do() with unknown outcome on non-idempotent request: err = %v, attempts = %d", err, attempts)
+	}
+	attempts = 0
+	_ = policy.Do(context.Background(), true, func() (*Response, error) {
+		attempts++
+		return serverError, errors.New("bad gateway")
+	})
+	if attempts != policy.MaxAttempts {
+		t.Errorf("Do() with unknown outcome on idempotent request: attempts = %d", attempts)
+	}
+	attempts = 0
+	_ = policy.Do(context.Background(), true, func() (*Response, error) {
+		attempts++
+		return nil, ParseSpotError("EOrder:Insufficient funds")
+	})
+	if attempts != 1 {
+		t.Errorf("Do() with permanent failure: attempts = %d", attempts)
+	}
+}
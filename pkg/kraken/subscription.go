@@ -0,0 +1,154 @@
+package kraken
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/krakenfx/api-go/v2/internal/helper"
+)
+
+// Subscription is a subscription request that is replayed after reconnecting.
+type Subscription struct {
+	Channel string         `json:"channel,omitempty"`
+	Symbol  string         `json:"symbol,omitempty"`
+	Private bool           `json:"private,omitempty"`
+	Request map[string]any `json:"request,omitempty"`
+}
+
+// SubscriptionRegistry keeps the subscription requests of a WebSocket client in the order they were sent.
+type SubscriptionRegistry struct {
+	subscriptions []*Subscription
+	keys          map[string]bool
+	mux           sync.Mutex
+}
+
+// NewSubscriptionRegistry constructs an empty [SubscriptionRegistry].
+func NewSubscriptionRegistry() *SubscriptionRegistry {
+	return &SubscriptionRegistry{
+		keys: make(map[string]bool),
+	}
+}
+
+func (s *Subscription) key() string {
+	return helper.ToJSON(s)
+}
+
+// Add stores a subscription unless an identical request is already stored.
+func (r *SubscriptionRegistry) Add(s *Subscription) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	key := s.key()
+	if r.keys[key] {
+		return
+	}
+	r.keys[key] = true
+	r.subscriptions = append(r.subscriptions, s)
+}
+
+// RemoveFunc removes the subscriptions for which remove returns true.
+func (r *SubscriptionRegistry) RemoveFunc(remove func(*Subscription) bool) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var kept []*Subscription
+	for _, s := range r.subscriptions {
+		if remove(s) {
+			delete(r.keys, s.key())
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	r.subscriptions = kept
+}
+
+// List returns a copy of the stored subscriptions.
+func (r *SubscriptionRegistry) List() []*Subscription {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]*Subscription(nil), r.subscriptions...)
+}
+
+// Reset removes all subscriptions.
+func (r *SubscriptionRegistry) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.subscriptions = nil
+	r.keys = make(map[string]bool)
+}
+
+// SubscriptionState records the channels and symbols whose subscriptions were confirmed by the server.
+// Channels without symbols are recorded with an empty symbol.
+type SubscriptionState struct {
+	channels map[string]map[string]bool
+	mux      sync.RWMutex
+}
+
+// NewSubscriptionState constructs an empty [SubscriptionState].
+func NewSubscriptionState() *SubscriptionState {
+	return &SubscriptionState{
+		channels: make(map[string]map[string]bool),
+	}
+}
+
+// Set records a symbol of a channel as subscribed or unsubscribed.
+// Unsubscribing with an empty symbol removes the whole channel.
+func (s *SubscriptionState) Set(channel string, symbol string, subscribed bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !subscribed {
+		if symbol == "" {
+			delete(s.channels, channel)
+			return
+		}
+		delete(s.channels[channel], symbol)
+		if len(s.channels[channel]) == 0 {
+			delete(s.channels, channel)
+		}
+		return
+	}
+	if s.channels[channel] == nil {
+		s.channels[channel] = make(map[string]bool)
+	}
+	s.channels[channel][symbol] = true
+}
+
+// IsSubscribed returns whether a symbol of a channel is subscribed.
+func (s *SubscriptionState) IsSubscribed(channel string, symbol string) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.channels[channel][symbol]
+}
+
+// Map returns the subscribed symbols by channel in ascending order.
+func (s *SubscriptionState) Map() map[string][]string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	result := make(map[string][]string, len(s.channels))
+	for channel, symbols := range s.channels {
+		list := make([]string, 0, len(symbols))
+		for symbol := range symbols {
+			list = append(list, symbol)
+		}
+		slices.Sort(list)
+		result[channel] = list
+	}
+	return result
+}
+
+// Reset removes all channels.
+func (s *SubscriptionState) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.channels = make(map[string]map[string]bool)
+}
+
+// RestoreEvent describes the session state restored after reconnecting.
+type RestoreEvent struct {
+	// Whether the client authenticated again before replaying private subscriptions.
+	Authenticated bool `json:"authenticated,omitempty"`
+
+	// Subscriptions that were sent again.
+	Subscriptions []*Subscription `json:"subscriptions,omitempty"`
+
+	// Errors that occurred while authenticating or resubscribing.
+	Err error `json:"-"`
+}
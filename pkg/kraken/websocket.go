@@ -5,9 +5,12 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -20,16 +23,30 @@ type WebSocket struct {
 	ReconnectWait time.Duration
 	DoReconnect   bool
 
+	// Interval between WebSocket ping frames and calls of Heartbeat. Disabled if 0.
+	PingInterval time.Duration
+
+	// Sends an application-level keepalive message every PingInterval.
+	Heartbeat func() error
+
+	// Maximum time without receiving a message or pong before the connection is closed as stale and reconnected. Disabled if 0.
+	StaleTimeout time.Duration
+
 	OnConnected    *callback.Manager[any]
+	OnReconnected  *callback.Manager[any]
 	OnDisconnected *callback.Manager[error]
 	OnSent         *callback.Manager[*WebSocketMessage]
 	OnReceived     *callback.Manager[*WebSocketMessage]
 
+	// Called with the time since the last message when the connection is closed as stale.
+	OnStale *callback.Manager[time.Duration]
+
 	conn     *websocket.Conn
 	URL      string
 	writeMux sync.Mutex
 	Insecure bool
-	active   bool
+	active   atomic.Bool
+	received atomic.Int64
 }
 
 // NewWebSocket creates a new [WebSocket] object with default values.
@@ -37,9 +54,11 @@ func NewWebSocket() *WebSocket {
 	ws := &WebSocket{
 		ReconnectWait:  2 * time.Second,
 		OnConnected:    callback.NewManager[any](),
+		OnReconnected:  callback.NewManager[any](),
 		OnDisconnected: callback.NewManager[error](),
 		OnSent:         callback.NewManager[*WebSocketMessage](),
 		OnReceived:     callback.NewManager[*WebSocketMessage](),
+		OnStale:        callback.NewManager[time.Duration](),
 	}
 	ws.Reconnect = func() {
 		for {
@@ -52,6 +71,7 @@ func NewWebSocket() *WebSocket {
 	ws.OnDisconnected.Recurring(func(e *callback.Event[error]) {
 		if ws.Reconnect != nil && !websocket.IsCloseError(e.Data, websocket.CloseNormalClosure) && ws.DoReconnect {
 			ws.Reconnect()
+			ws.OnReconnected.Call(nil)
 		}
 	})
 	return ws
@@ -75,9 +95,17 @@ func (ws *WebSocket) Connect() error {
 	if err != nil {
 		return fmt.Errorf("dial failed: %s", err)
 	}
+	ws.writeMux.Lock()
 	ws.conn = connection
+	ws.writeMux.Unlock()
 	ws.DoReconnect = true
-	go ws.read()
+	ws.active.Store(true)
+	ws.touch(connection)
+	connection.SetPongHandler(func(string) error {
+		ws.touch(connection)
+		return nil
+	})
+	go ws.read(connection)
 	ws.OnConnected.Call(nil)
 	return nil
 }
@@ -125,22 +153,63 @@ func (m *WebSocketMessage) Map() (map[string]any, error) {
 	return dataMapped, nil
 }
 
-func (ws *WebSocket) read() {
-	ws.active = true
-	defer func() {
-		ws.active = false
-	}()
+func (ws *WebSocket) read(conn *websocket.Conn) {
+	stop := make(chan struct{})
+	go ws.keepAlive(conn, stop)
 	for {
-		_, data, err := ws.conn.ReadMessage()
+		_, data, err := conn.ReadMessage()
 		if err != nil {
-			_ = ws.conn.Close()
+			close(stop)
+			_ = conn.Close()
+			ws.active.Store(false)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				ws.OnStale.Call(time.Since(ws.LastReceived()))
+			}
 			ws.OnDisconnected.Call(err)
 			return
 		}
+		ws.touch(conn)
 		ws.OnReceived.Call(NewWebSocketMessage(data))
 	}
 }
 
+// touch records the time of the last message and extends the read deadline by StaleTimeout.
+func (ws *WebSocket) touch(conn *websocket.Conn) {
+	now := time.Now()
+	ws.received.Store(now.UnixNano())
+	if ws.StaleTimeout > 0 {
+		_ = conn.SetReadDeadline(now.Add(ws.StaleTimeout))
+	}
+}
+
+// keepAlive sends a ping frame and calls Heartbeat every PingInterval until stop is closed.
+func (ws *WebSocket) keepAlive(conn *websocket.Conn, stop chan struct{}) {
+	if ws.PingInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(ws.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(ws.PingInterval)); err != nil {
+			continue
+		}
+		if ws.Heartbeat != nil {
+			_ = ws.Heartbeat()
+		}
+	}
+}
+
+// LastReceived returns the time of the last message or pong received.
+func (ws *WebSocket) LastReceived() time.Time {
+	return time.Unix(0, ws.received.Load())
+}
+
 // Disconnect stops the connection.
 func (ws *WebSocket) Disconnect() error {
 	ws.DoReconnect = false
@@ -166,7 +235,7 @@ func (ws *WebSocket) Disconnect() error {
 
 // IsActive returns the status of the connection.
 func (ws *WebSocket) IsActive() bool {
-	return ws.active
+	return ws.active.Load()
 }
 
 // WriteJSON submits a message to the connection.
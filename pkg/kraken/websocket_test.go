@@ -0,0 +1,53 @@
+package kraken
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+func TestWebSocketStale(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %s", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		<-release
+	}))
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	ws.Reconnect = nil
+	ws.PingInterval = 20 * time.Millisecond
+	ws.StaleTimeout = 100 * time.Millisecond
+	stale := make(chan time.Duration, 1)
+	ws.OnStale.Recurring(func(e *callback.Event[time.Duration]) {
+		stale <- e.Data
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	select {
+	case elapsed := <-stale:
+		if elapsed < ws.StaleTimeout {
+			t.Errorf("expected at least %s since the last message, got %s", ws.StaleTimeout, elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for stale connection")
+	}
+	if ws.IsActive() {
+		t.Errorf("expected stale connection to be inactive")
+	}
+}
@@ -0,0 +1,146 @@
+package spot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+// Updates converts the price levels to [book.UpdateOptions] for [book.Book.Replace].
+func (ob *OrderBook) Updates() []*book.UpdateOptions {
+	var updates []*book.UpdateOptions
+	sides := []struct {
+		direction book.BookDirection
+		levels    []PriceLevel
+	}{{book.Bid, ob.Bids}, {book.Ask, ob.Asks}}
+	for _, side := range sides {
+		for _, level := range side.levels {
+			updates = append(updates, &book.UpdateOptions{
+				Direction: side.direction,
+				Price:     level.Price,
+				Quantity:  level.Volume,
+				Timestamp: level.Timestamp,
+			})
+		}
+	}
+	return updates
+}
+
+// Number of price levels per side returned by [REST.OrderBook] if the count is zero.
+const DefaultOrderBookCount = 100
+
+// LoadOrderBook replaces the levels of the managed book of a symbol with a REST [OrderBook], creating the book if needed.
+// A new book keeps the requested count of price levels per side, or [DefaultOrderBookCount] if zero.
+// The differences with the previous levels are passed to OnUpdated of the book.
+func (bm *BookManager) LoadOrderBook(symbol string, ob *OrderBook, count int) *book.Book {
+	b := bm.GetBook(symbol)
+	if b == nil {
+		if count <= 0 {
+			count = DefaultOrderBookCount
+		}
+		b = bm.CreateBook(symbol, count)
+	}
+	b.Replace(ob.Updates())
+	bm.mux.Lock()
+	bm.status(b.Name).Health = BookHealthy
+	bm.mux.Unlock()
+	return b
+}
+
+// BookPoller maintains the books of a [BookManager] by polling the REST order book, which replaces WebSocket subscriptions where they are unavailable.
+// Each poll applies the differences with the previous one, so OnUpdated of the books is called for each changed price level.
+type BookPoller struct {
+	Symbols []string
+
+	// Number of price levels per side, or the API default if zero.
+	Count int
+
+	// Interval between polls.
+	Interval time.Duration
+
+	Manager  *BookManager
+	OnPolled *callback.Manager[*book.Book]
+	OnError  *callback.Manager[error]
+
+	rest *REST
+	stop chan struct{}
+	done chan struct{}
+	mux  sync.Mutex
+}
+
+// NewBookPoller constructs a [BookPoller] polling the given symbols every second into a new [BookManager].
+func NewBookPoller(rest *REST, symbols ...string) *BookPoller {
+	return &BookPoller{
+		Symbols:  symbols,
+		Interval: time.Second,
+		Manager:  NewBookManager(),
+		OnPolled: callback.NewManager[*book.Book](),
+		OnError:  callback.NewManager[error](),
+		rest:     rest,
+	}
+}
+
+// Start polls the books every interval in a new goroutine.
+func (p *BookPoller) Start() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.stop != nil {
+		return fmt.Errorf("book poller already started")
+	}
+	if p.Interval <= 0 {
+		return fmt.Errorf("interval %s must be positive", p.Interval)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	p.stop = stop
+	p.done = done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.Interval)
+		defer ticker.Stop()
+		for {
+			for _, symbol := range p.Symbols {
+				if err := p.Poll(symbol); err != nil {
+					p.OnError.Call(err)
+				}
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Poll retrieves the order book of a symbol once and loads it into the managed book.
+func (p *BookPoller) Poll(symbol string) error {
+	resp, err := p.rest.OrderBook(&OrderBookRequest{
+		Pair:  symbol,
+		Count: p.Count,
+	})
+	if err != nil {
+		return fmt.Errorf("\"%s\" order book: %w", symbol, err)
+	}
+	for _, ob := range resp.Result {
+		p.OnPolled.Call(p.Manager.LoadOrderBook(symbol, &ob, p.Count))
+		return nil
+	}
+	return fmt.Errorf("\"%s\" order book: empty result", symbol)
+}
+
+// Stop stops polling and waits for the current poll to finish.
+func (p *BookPoller) Stop() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	p.stop = nil
+}
@@ -0,0 +1,58 @@
+package spot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+func TestBookPoller(t *testing.T) {
+	var polls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch polls.Add(1) {
+		case 1:
+			_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"asks":[["101.0","1.0",1700000000]],"bids":[["100.0","1.0",1700000000],["99.0","2.0",1700000000]]}}}`))
+		case 2:
+			_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"asks":[["101.0","1.5",1700000001]],"bids":[["100.0","1.0",1700000000]]}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"asks":[["101.0","1.5",1700000001]],"bids":[["100.0","1.0",1700000000],["99.0","2.0",1700000002],["98.0","3.0",1700000002],["97.0","4.0",1700000002]]}}}`))
+		}
+	}))
+	defer server.Close()
+	rest := NewREST()
+	rest.BaseURL = server.URL
+	poller := NewBookPoller(rest, "BTC/USD")
+	var updates []*book.UpdateOptions
+	poller.Manager.OnCreateBook.Recurring(func(e *callback.Event[*book.Book]) {
+		e.Data.OnUpdated.Recurring(func(e *callback.Event[*book.UpdateOptions]) {
+			updates = append(updates, e.Data)
+		})
+	})
+	if err := poller.Poll("BTC/USD"); err != nil {
+		t.Fatalf("Poll: %s", err)
+	}
+	if len(updates) != 3 {
+		t.Errorf("expected 3 updates from the first poll, got %d", len(updates))
+	}
+	updates = nil
+	if err := poller.Poll("BTC/USD"); err != nil {
+		t.Fatalf("Poll: %s", err)
+	}
+	if len(updates) != 2 || updates[0].Price.Int64() != 99 || updates[0].Quantity.Sign() != 0 || updates[1].Price.Int64() != 101 {
+		t.Errorf("expected the removal of 99 and the update of 101, got %d updates", len(updates))
+	}
+	b := poller.Manager.GetBook("BTC/USD")
+	if b.Bids.Len() != 1 || b.BestAsk().Quantity.Cmp(updates[1].Quantity) != 0 {
+		t.Errorf("expected the book to match the second poll")
+	}
+	if err := poller.Poll("BTC/USD"); err != nil {
+		t.Fatalf("Poll: %s", err)
+	}
+	if b.MaxDepth != DefaultOrderBookCount || b.Bids.Len() != 4 {
+		t.Errorf("expected deeper polls to be kept up to the default count, got %d bids", b.Bids.Len())
+	}
+}
@@ -2,6 +2,7 @@ package spot
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -15,18 +16,82 @@ import (
 	"github.com/krakenfx/api-go/v2/pkg/kraken"
 )
 
+// ErrChecksumFailed is wrapped by the errors of book updates whose checksum does not match the server.
+var ErrChecksumFailed = errors.New("checksum failed")
+
 // BookManager manages the lifecycle of a collection of [Book] structs.
 type BookManager struct {
 	books        map[string]*book.Book
+	statuses     map[string]*BookStatus
 	mux          sync.RWMutex
 	OnCreateBook *callback.Manager[*book.Book]
+
+	// Whether a book failing its checksum is resubscribed with a snapshot through WebSocket instead of returning an error.
+	// Updates of the book are discarded until the snapshot arrives.
+	Resync    bool
+	WebSocket *WebSocket
+
+	// Called when a book fails its checksum.
+	OnDesync *callback.Manager[*DesyncEvent]
+
+	// Called for each order of the level3 messages once the message is applied to the book and matches its checksum.
+	OnOrderEvent *callback.Manager[*OrderEvent]
+}
+
+// OrderEvent is an add, modify or delete event of an individual order in a level3 book.
+type OrderEvent struct {
+	Symbol string `json:"symbol,omitempty"`
+
+	// Type of the event, which is empty for the orders of a snapshot.
+	Event string `json:"event,omitempty"`
+
+	Direction book.BookDirection `json:"direction,omitempty"`
+	OrderID   string             `json:"orderId,omitempty"`
+	Price     *decimal.Decimal   `json:"price,omitempty"`
+	Quantity  *decimal.Decimal   `json:"quantity,omitempty"`
+	Timestamp time.Time          `json:"timestamp,omitempty"`
+}
+
+// BookHealth is the synchronization state of a managed book.
+type BookHealth string
+
+const (
+	// The book matched the last checksum.
+	BookHealthy BookHealth = "healthy"
+
+	// The book failed a checksum and is not resynchronized.
+	BookInvalid BookHealth = "invalid"
+
+	// The book failed a checksum and awaits a snapshot.
+	BookResyncing BookHealth = "resyncing"
+)
+
+// BookStatus contains the health and counters of a managed book.
+type BookStatus struct {
+	Health     BookHealth `json:"health,omitempty"`
+	Desyncs    int        `json:"desyncs,omitempty"`
+	Resyncs    int        `json:"resyncs,omitempty"`
+	Discarded  int        `json:"discarded,omitempty"`
+	LastDesync time.Time  `json:"lastDesync,omitempty"`
+}
+
+// DesyncEvent is emitted by [BookManager.OnDesync] when a book fails its checksum.
+type DesyncEvent struct {
+	Book    *book.Book `json:"-"`
+	Channel string     `json:"channel,omitempty"`
+	Err     error      `json:"-"`
+	Desyncs int        `json:"desyncs,omitempty"`
+	Resync  bool       `json:"resync,omitempty"`
 }
 
 // NewBookManager constructs a new [BookManager] struct.
 func NewBookManager() *BookManager {
 	return &BookManager{
 		books:        make(map[string]*book.Book),
+		statuses:     make(map[string]*BookStatus),
 		OnCreateBook: callback.NewManager[*book.Book](),
+		OnDesync:     callback.NewManager[*DesyncEvent](),
+		OnOrderEvent: callback.NewManager[*OrderEvent](),
 	}
 }
 
@@ -60,6 +125,9 @@ func (b *BookManager) Update(m *callback.Event[*kraken.WebSocketMessage]) error
 			}
 		}
 		for _, symbol := range *symbols {
+			if b.Health(fmt.Sprint(symbol)) == BookResyncing {
+				continue
+			}
 			b.CreateBook(fmt.Sprint(symbol), int(depthInt))
 		}
 		return nil
@@ -72,6 +140,8 @@ func (b *BookManager) Update(m *callback.Event[*kraken.WebSocketMessage]) error
 	if err != nil {
 		return err
 	}
+	messageType, _ := helper.Traverse[string](event, "type")
+	snapshot := messageType != nil && *messageType == "snapshot"
 	for _, update := range *updates {
 		bookUpdate, ok := update.(map[string]any)
 		if !ok {
@@ -85,20 +155,145 @@ func (b *BookManager) Update(m *callback.Event[*kraken.WebSocketMessage]) error
 		if book == nil {
 			return fmt.Errorf("%s not found in library (%s)", *symbol, strings.Join(b.GetBooks(), ","))
 		}
+		if !b.accept(book, snapshot) {
+			continue
+		}
 		switch *channel {
 		case "level3":
-			if err := b.UpdateL3(book, bookUpdate); err != nil {
-				return fmt.Errorf("\"%s\" update l3: %w", *symbol, err)
+			err = b.UpdateL3(book, bookUpdate)
+			if err != nil {
+				err = fmt.Errorf("\"%s\" update l3: %w", *symbol, err)
 			}
 		case "book":
-			if err := b.UpdateL2(book, bookUpdate); err != nil {
-				return fmt.Errorf("\"%s\" update l2: %w", *symbol, err)
+			err = b.UpdateL2(book, bookUpdate)
+			if err != nil {
+				err = fmt.Errorf("\"%s\" update l2: %w", *symbol, err)
 			}
 		}
+		switch {
+		case errors.Is(err, ErrChecksumFailed):
+			if err := b.desync(book, *channel, err); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case snapshot:
+			b.synced(book)
+		}
 	}
 	return nil
 }
 
+// accept reports whether an update should be applied to a book, which discards the updates of resyncing books until a snapshot arrives.
+// The book is cleared before applying a snapshot.
+func (b *BookManager) accept(bk *book.Book, snapshot bool) bool {
+	b.mux.Lock()
+	status := b.status(bk.Name)
+	if !snapshot && status.Health == BookResyncing {
+		status.Discarded++
+		b.mux.Unlock()
+		return false
+	}
+	b.mux.Unlock()
+	if snapshot {
+		bk.Clear()
+	}
+	return true
+}
+
+// synced marks a book as healthy after a snapshot matched its checksum.
+func (b *BookManager) synced(bk *book.Book) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	status := b.status(bk.Name)
+	if status.Health == BookResyncing {
+		status.Resyncs++
+	}
+	status.Health = BookHealthy
+}
+
+// desync marks a book as invalid after a checksum failure and resubscribes it if Resync is enabled.
+// It returns the checksum error if the book is not resubscribed.
+func (b *BookManager) desync(bk *book.Book, channel string, cause error) error {
+	b.mux.Lock()
+	status := b.status(bk.Name)
+	status.Desyncs++
+	status.LastDesync = time.Now()
+	event := &DesyncEvent{
+		Book:    bk,
+		Channel: channel,
+		Err:     cause,
+		Desyncs: status.Desyncs,
+		Resync:  b.Resync && b.WebSocket != nil,
+	}
+	if event.Resync {
+		status.Health = BookResyncing
+	} else {
+		status.Health = BookInvalid
+	}
+	b.mux.Unlock()
+	b.OnDesync.Call(event)
+	if !event.Resync {
+		return cause
+	}
+	if err := b.resubscribe(bk, channel); err != nil {
+		return fmt.Errorf("\"%s\" resync: %w", bk.Name, err)
+	}
+	return nil
+}
+
+// resubscribe unsubscribes and subscribes a book again through the linked WebSocket to receive a new snapshot.
+func (b *BookManager) resubscribe(bk *book.Book, channel string) error {
+	symbols := []string{bk.Name}
+	switch channel {
+	case "level3":
+		if err := b.WebSocket.UnsubL3(symbols); err != nil {
+			return fmt.Errorf("unsubscribe: %w", err)
+		}
+		if err := b.WebSocket.SubL3(symbols, bk.MaxDepth); err != nil {
+			return fmt.Errorf("subscribe: %w", err)
+		}
+	default:
+		if err := b.WebSocket.UnsubBook(symbols, bk.MaxDepth); err != nil {
+			return fmt.Errorf("unsubscribe: %w", err)
+		}
+		if err := b.WebSocket.SubBook(symbols, bk.MaxDepth); err != nil {
+			return fmt.Errorf("subscribe: %w", err)
+		}
+	}
+	return nil
+}
+
+// status returns the status of a book while the manager is locked, creating it if needed.
+func (b *BookManager) status(name string) *BookStatus {
+	status, ok := b.statuses[name]
+	if !ok {
+		status = &BookStatus{Health: BookHealthy}
+		b.statuses[name] = status
+	}
+	return status
+}
+
+// Status returns a copy of the status of the book associated with the given symbol or nil if there is none.
+func (b *BookManager) Status(symbol string) *BookStatus {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	status, ok := b.statuses[strings.ToUpper(symbol)]
+	if !ok {
+		return nil
+	}
+	copied := *status
+	return &copied
+}
+
+// Health returns the health of the book associated with the given symbol, which is healthy for unknown books.
+func (b *BookManager) Health(symbol string) BookHealth {
+	if status := b.Status(symbol); status != nil {
+		return status.Health
+	}
+	return BookHealthy
+}
+
 // CreateBook constructs a managed [Book] struct.
 func (b *BookManager) CreateBook(name string, depth int) *book.Book {
 	b.mux.Lock()
@@ -109,6 +304,7 @@ func (b *BookManager) CreateBook(name string, depth int) *book.Book {
 	book.EnableMaxDepth = true
 	book.MaxDepth = depth
 	b.books[nameUpper] = book
+	b.status(nameUpper).Health = BookHealthy
 	b.OnCreateBook.Call(book)
 	return book
 }
@@ -191,7 +387,7 @@ func (bm *BookManager) UpdateL2(b *book.Book, m map[string]any) error {
 		return err
 	}
 	if result := b.L2Checksum(serverChecksum.String()); !result.Match {
-		return fmt.Errorf("checksum failed, server \"%s\" versus local \"%s\"", result.ServerChecksum, result.LocalChecksum)
+		return fmt.Errorf("%w, server \"%s\" versus local \"%s\"", ErrChecksumFailed, result.ServerChecksum, result.LocalChecksum)
 	}
 	return nil
 }
@@ -210,6 +406,7 @@ func (bm *BookManager) UpdateL3(b *book.Book, m map[string]any) error {
 		book.Bid: *bids,
 		book.Ask: *asks,
 	}
+	var orderEvents []*OrderEvent
 	for direction, records := range sides {
 		for _, record := range records {
 			id, err := helper.Traverse[string](record, "order_id")
@@ -251,6 +448,18 @@ func (bm *BookManager) UpdateL3(b *book.Book, m map[string]any) error {
 				Quantity:  quantityDecimal,
 				Timestamp: timestamp,
 			})
+			orderEvent := &OrderEvent{
+				Symbol:    b.Name,
+				Direction: direction,
+				OrderID:   *id,
+				Price:     priceDecimal,
+				Quantity:  quantityDecimal,
+				Timestamp: timestamp,
+			}
+			if event != nil {
+				orderEvent.Event = *event
+			}
+			orderEvents = append(orderEvents, orderEvent)
 		}
 	}
 	serverChecksum, err := helper.Traverse[json.Number](m, "checksum")
@@ -258,7 +467,10 @@ func (bm *BookManager) UpdateL3(b *book.Book, m map[string]any) error {
 		return err
 	}
 	if result := b.L3Checksum(serverChecksum.String()); !result.Match {
-		return fmt.Errorf("checksum failed, server \"%s\" versus local \"%s\"", result.ServerChecksum, result.LocalChecksum)
+		return fmt.Errorf("%w, server \"%s\" versus local \"%s\"", ErrChecksumFailed, result.ServerChecksum, result.LocalChecksum)
+	}
+	for _, orderEvent := range orderEvents {
+		bm.OnOrderEvent.Call(orderEvent)
 	}
 	return nil
 }
@@ -0,0 +1,107 @@
+package spot
+
+import (
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+func TestBookManagerResync(t *testing.T) {
+	expected := book.New()
+	for direction, price := range map[book.BookDirection]string{book.Bid: "100.5", book.Ask: "101.5"} {
+		priceDecimal, _ := decimal.NewFromString(price)
+		quantity, _ := decimal.NewFromString("1.25")
+		expected.Update(&book.UpdateOptions{Direction: direction, Price: priceDecimal, Quantity: quantity})
+	}
+	checksum := expected.L2Checksum("").LocalChecksum
+	message := func(messageType string, bids []any, checksum string) map[string]any {
+		return map[string]any{
+			"channel": "book",
+			"type":    messageType,
+			"data": []any{map[string]any{
+				"symbol":   "BTC/USD",
+				"bids":     bids,
+				"asks":     []any{map[string]any{"price": 101.5, "qty": 1.25}},
+				"checksum": json.Number(checksum),
+			}},
+		}
+	}
+	var subscriptions atomic.Int64
+	server := newTestServer(t, func(request map[string]any) []any {
+		switch request["method"] {
+		case "ping":
+			return []any{message("update", []any{map[string]any{"price": 100.5, "qty": 2}}, "1")}
+		case "subscribe":
+			snapshot := message("snapshot", []any{map[string]any{"price": 100.5, "qty": 1.25}}, checksum)
+			if subscriptions.Add(1) == 1 {
+				return []any{snapshot}
+			}
+			return []any{message("update", []any{map[string]any{"price": 99, "qty": 1}}, "1"), snapshot}
+		}
+		return nil
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	bm := NewBookManager()
+	bm.Resync = true
+	bm.WebSocket = ws
+	errs := make(chan error, 10)
+	update := func(e *callback.Event[*kraken.WebSocketMessage]) {
+		if err := bm.Update(e); err != nil {
+			errs <- err
+		}
+	}
+	ws.OnSent.Recurring(update)
+	ws.OnReceived.Recurring(update)
+	desyncs := make(chan *DesyncEvent, 1)
+	bm.OnDesync.Recurring(func(e *callback.Event[*DesyncEvent]) {
+		desyncs <- e.Data
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	if err := ws.SubBook([]string{"BTC/USD"}, 10); err != nil {
+		t.Fatalf("SubBook: %s", err)
+	}
+	b := bm.GetBook("BTC/USD")
+	if err := ws.Ping(); err != nil {
+		t.Fatalf("Ping: %s", err)
+	}
+	select {
+	case event := <-desyncs:
+		if event.Book != b || !event.Resync || event.Desyncs != 1 {
+			t.Errorf("expected a resync of the book, got %+v", event)
+		}
+	case err := <-errs:
+		t.Fatalf("Update: %s", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a desync event")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for bm.Status("BTC/USD").Resyncs == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	status := bm.Status("BTC/USD")
+	if status.Health != BookHealthy || status.Resyncs != 1 || status.Discarded != 1 {
+		t.Errorf("expected a healthy book after discarding 1 update, got %+v", status)
+	}
+	if bm.GetBook("BTC/USD") != b || b.BestBid().Quantity.Cmp(decimal.NewFromFloat64(1.25)) != 0 || b.Bids.Len() != 1 {
+		t.Errorf("expected the same book restored from the snapshot")
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("Update: %s", err)
+	default:
+	}
+}
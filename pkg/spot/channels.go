@@ -0,0 +1,229 @@
+package spot
+
+import (
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// ChannelMessage is the envelope of the snapshots and updates of a WebSocket v2 channel.
+type ChannelMessage[T any] struct {
+	Channel  string `json:"channel,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Sequence int64  `json:"sequence,omitempty"`
+	Data     T      `json:"data,omitempty"`
+}
+
+type TickerData struct {
+	Symbol    string           `json:"symbol,omitempty"`
+	Bid       *decimal.Decimal `json:"bid,omitempty"`
+	BidQty    *decimal.Decimal `json:"bid_qty,omitempty"`
+	Ask       *decimal.Decimal `json:"ask,omitempty"`
+	AskQty    *decimal.Decimal `json:"ask_qty,omitempty"`
+	Last      *decimal.Decimal `json:"last,omitempty"`
+	Volume    *decimal.Decimal `json:"volume,omitempty"`
+	VWAP      *decimal.Decimal `json:"vwap,omitempty"`
+	Low       *decimal.Decimal `json:"low,omitempty"`
+	High      *decimal.Decimal `json:"high,omitempty"`
+	Change    *decimal.Decimal `json:"change,omitempty"`
+	ChangePct *decimal.Decimal `json:"change_pct,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/ticker
+type TickerUpdate = ChannelMessage[[]TickerData]
+
+type BookLevel struct {
+	Price *decimal.Decimal `json:"price,omitempty"`
+	Qty   *decimal.Decimal `json:"qty,omitempty"`
+}
+
+type BookData struct {
+	Symbol    string      `json:"symbol,omitempty"`
+	Bids      []BookLevel `json:"bids,omitempty"`
+	Asks      []BookLevel `json:"asks,omitempty"`
+	Checksum  uint32      `json:"checksum,omitempty"`
+	Timestamp time.Time   `json:"timestamp,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/book
+type BookUpdate = ChannelMessage[[]BookData]
+
+type Level3Order struct {
+	Event      string           `json:"event,omitempty"`
+	OrderID    string           `json:"order_id,omitempty"`
+	LimitPrice *decimal.Decimal `json:"limit_price,omitempty"`
+	OrderQty   *decimal.Decimal `json:"order_qty,omitempty"`
+	Timestamp  time.Time        `json:"timestamp,omitempty"`
+}
+
+type Level3Data struct {
+	Symbol    string        `json:"symbol,omitempty"`
+	Bids      []Level3Order `json:"bids,omitempty"`
+	Asks      []Level3Order `json:"asks,omitempty"`
+	Checksum  uint32        `json:"checksum,omitempty"`
+	Timestamp time.Time     `json:"timestamp,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/level3
+type Level3Update = ChannelMessage[[]Level3Data]
+
+type CandleData struct {
+	Symbol        string           `json:"symbol,omitempty"`
+	Open          *decimal.Decimal `json:"open,omitempty"`
+	High          *decimal.Decimal `json:"high,omitempty"`
+	Low           *decimal.Decimal `json:"low,omitempty"`
+	Close         *decimal.Decimal `json:"close,omitempty"`
+	VWAP          *decimal.Decimal `json:"vwap,omitempty"`
+	Trades        int              `json:"trades,omitempty"`
+	Volume        *decimal.Decimal `json:"volume,omitempty"`
+	IntervalBegin time.Time        `json:"interval_begin,omitempty"`
+	Interval      int              `json:"interval,omitempty"`
+	Timestamp     time.Time        `json:"timestamp,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/ohlc
+type CandleUpdate = ChannelMessage[[]CandleData]
+
+type TradeData struct {
+	Symbol    string           `json:"symbol,omitempty"`
+	Side      string           `json:"side,omitempty"`
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	Qty       *decimal.Decimal `json:"qty,omitempty"`
+	OrdType   string           `json:"ord_type,omitempty"`
+	TradeID   int64            `json:"trade_id,omitempty"`
+	Timestamp time.Time        `json:"timestamp,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/trade
+type TradeUpdate = ChannelMessage[[]TradeData]
+
+type InstrumentAsset struct {
+	ID               string           `json:"id,omitempty"`
+	Status           string           `json:"status,omitempty"`
+	Precision        int              `json:"precision,omitempty"`
+	PrecisionDisplay int              `json:"precision_display,omitempty"`
+	Borrowable       bool             `json:"borrowable,omitempty"`
+	CollateralValue  *decimal.Decimal `json:"collateral_value,omitempty"`
+	MarginRate       *decimal.Decimal `json:"margin_rate,omitempty"`
+}
+
+type InstrumentPair struct {
+	Symbol             string           `json:"symbol,omitempty"`
+	Base               string           `json:"base,omitempty"`
+	Quote              string           `json:"quote,omitempty"`
+	Status             string           `json:"status,omitempty"`
+	QtyPrecision       int              `json:"qty_precision,omitempty"`
+	QtyIncrement       *decimal.Decimal `json:"qty_increment,omitempty"`
+	QtyMin             *decimal.Decimal `json:"qty_min,omitempty"`
+	PricePrecision     int              `json:"price_precision,omitempty"`
+	PriceIncrement     *decimal.Decimal `json:"price_increment,omitempty"`
+	CostPrecision      int              `json:"cost_precision,omitempty"`
+	CostMin            *decimal.Decimal `json:"cost_min,omitempty"`
+	Marginable         bool             `json:"marginable,omitempty"`
+	HasIndex           bool             `json:"has_index,omitempty"`
+	MarginInitial      *decimal.Decimal `json:"margin_initial,omitempty"`
+	PositionLimitLong  int              `json:"position_limit_long,omitempty"`
+	PositionLimitShort int              `json:"position_limit_short,omitempty"`
+	TickSize           *decimal.Decimal `json:"tick_size,omitempty"`
+}
+
+type InstrumentData struct {
+	Assets []InstrumentAsset `json:"assets,omitempty"`
+	Pairs  []InstrumentPair  `json:"pairs,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/instrument
+type InstrumentUpdate = ChannelMessage[InstrumentData]
+
+type ExecutionFee struct {
+	Asset string           `json:"asset,omitempty"`
+	Qty   *decimal.Decimal `json:"qty,omitempty"`
+}
+
+type ExecutionData struct {
+	ExecType       string             `json:"exec_type,omitempty"`
+	ExecID         string             `json:"exec_id,omitempty"`
+	TradeID        int64              `json:"trade_id,omitempty"`
+	OrderID        string             `json:"order_id,omitempty"`
+	ClOrdID        string             `json:"cl_ord_id,omitempty"`
+	OrderUserref   int                `json:"order_userref,omitempty"`
+	Symbol         string             `json:"symbol,omitempty"`
+	Side           string             `json:"side,omitempty"`
+	OrderType      string             `json:"order_type,omitempty"`
+	OrderStatus    string             `json:"order_status,omitempty"`
+	OrderQty       *decimal.Decimal   `json:"order_qty,omitempty"`
+	CashOrderQty   *decimal.Decimal   `json:"cash_order_qty,omitempty"`
+	DisplayQty     *decimal.Decimal   `json:"display_qty,omitempty"`
+	LimitPrice     *decimal.Decimal   `json:"limit_price,omitempty"`
+	LimitPriceType string             `json:"limit_price_type,omitempty"`
+	Triggers       *WebSocketTriggers `json:"triggers,omitempty"`
+	TimeInForce    string             `json:"time_in_force,omitempty"`
+	PostOnly       bool               `json:"post_only,omitempty"`
+	ReduceOnly     bool               `json:"reduce_only,omitempty"`
+	Margin         bool               `json:"margin,omitempty"`
+	LastQty        *decimal.Decimal   `json:"last_qty,omitempty"`
+	LastPrice      *decimal.Decimal   `json:"last_price,omitempty"`
+	LiquidityInd   string             `json:"liquidity_ind,omitempty"`
+	Cost           *decimal.Decimal   `json:"cost,omitempty"`
+	CumQty         *decimal.Decimal   `json:"cum_qty,omitempty"`
+	CumCost        *decimal.Decimal   `json:"cum_cost,omitempty"`
+	AvgPrice       *decimal.Decimal   `json:"avg_price,omitempty"`
+	Fees           []ExecutionFee     `json:"fees,omitempty"`
+	FeeUSDEquiv    *decimal.Decimal   `json:"fee_usd_equiv,omitempty"`
+	Amended        bool               `json:"amended,omitempty"`
+	Reason         string             `json:"reason,omitempty"`
+	Timestamp      time.Time          `json:"timestamp,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/executions
+type ExecutionsUpdate = ChannelMessage[[]ExecutionData]
+
+type BalanceWallet struct {
+	Type    string           `json:"type,omitempty"`
+	ID      string           `json:"id,omitempty"`
+	Balance *decimal.Decimal `json:"balance,omitempty"`
+}
+
+// BalanceData is an asset balance in snapshots or a ledger entry in updates.
+type BalanceData struct {
+	Asset      string           `json:"asset,omitempty"`
+	AssetClass string           `json:"asset_class,omitempty"`
+	Balance    *decimal.Decimal `json:"balance,omitempty"`
+	Wallets    []BalanceWallet  `json:"wallets,omitempty"`
+	LedgerID   string           `json:"ledger_id,omitempty"`
+	RefID      string           `json:"ref_id,omitempty"`
+	Type       string           `json:"type,omitempty"`
+	Subtype    string           `json:"subtype,omitempty"`
+	Category   string           `json:"category,omitempty"`
+	WalletType string           `json:"wallet_type,omitempty"`
+	WalletID   string           `json:"wallet_id,omitempty"`
+	Amount     *decimal.Decimal `json:"amount,omitempty"`
+	Fee        *decimal.Decimal `json:"fee,omitempty"`
+	Timestamp  time.Time        `json:"timestamp,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/balances
+type BalancesUpdate = ChannelMessage[[]BalanceData]
+
+type StatusData struct {
+	System       string `json:"system,omitempty"`
+	APIVersion   string `json:"api_version,omitempty"`
+	ConnectionID uint64 `json:"connection_id,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+// https://docs.kraken.com/api/docs/websocket-v2/status
+type StatusUpdate = ChannelMessage[[]StatusData]
+
+// https://docs.kraken.com/api/docs/websocket-v2/heartbeat
+type HeartbeatUpdate = ChannelMessage[any]
+
+// SubscriptionResult is the result of a subscribe or unsubscribe request.
+type SubscriptionResult struct {
+	Channel  string   `json:"channel,omitempty"`
+	Symbol   string   `json:"symbol,omitempty"`
+	Snapshot bool     `json:"snapshot,omitempty"`
+	Depth    int      `json:"depth,omitempty"`
+	Interval int      `json:"interval,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
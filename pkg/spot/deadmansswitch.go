@@ -0,0 +1,115 @@
+package spot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+)
+
+// DeadMansSwitch keeps the cancel_all_orders_after timer of a [WebSocket] armed while the connection is active.
+// If the connection is lost or the process stops, the exchange cancels all open orders once the timeout expires.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/cancel_after
+type DeadMansSwitch struct {
+	// Countdown sent with each request. Must be at least 1 second.
+	Timeout time.Duration
+
+	// Interval between requests which must be shorter than the timeout.
+	Interval time.Duration
+
+	OnArmed *callback.Manager[*WebSocketResponse[WebSocketCancelAfterResult]]
+	OnError *callback.Manager[error]
+
+	ws   *WebSocket
+	stop chan struct{}
+	done chan struct{}
+	mux  sync.Mutex
+}
+
+// NewDeadMansSwitch constructs a [DeadMansSwitch] with a 60 second timeout refreshed every 15 seconds.
+func NewDeadMansSwitch(ws *WebSocket) *DeadMansSwitch {
+	return &DeadMansSwitch{
+		Timeout:  60 * time.Second,
+		Interval: 15 * time.Second,
+		OnArmed:  callback.NewManager[*WebSocketResponse[WebSocketCancelAfterResult]](),
+		OnError:  callback.NewManager[error](),
+		ws:       ws,
+	}
+}
+
+// Start arms the timer and refreshes it every interval in a new goroutine.
+// Refreshes are skipped while the connection is inactive or unauthenticated so that the timer can expire.
+func (d *DeadMansSwitch) Start() error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.stop != nil {
+		return fmt.Errorf("dead man's switch already started")
+	}
+	if d.Timeout < time.Second {
+		return fmt.Errorf("timeout %s must be at least 1 second", d.Timeout)
+	}
+	if d.Interval <= 0 || d.Interval >= d.Timeout {
+		return fmt.Errorf("interval %s must be positive and shorter than timeout %s", d.Interval, d.Timeout)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	d.stop = stop
+	d.done = done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(d.Interval)
+		defer ticker.Stop()
+		for {
+			d.arm()
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// arm sends a single cancel_all_orders_after request and waits for its response.
+func (d *DeadMansSwitch) arm() {
+	if !d.ws.IsActive() || d.ws.CurrentToken() == "" {
+		return
+	}
+	pending, err := d.ws.CancelAllOrdersAfter(d.Timeout)
+	if err != nil {
+		d.OnError.Call(fmt.Errorf("arm dead man's switch: %w", err))
+		return
+	}
+	response, err := pending.Wait()
+	if err != nil {
+		d.OnError.Call(fmt.Errorf("arm dead man's switch: %w", err))
+		return
+	}
+	d.OnArmed.Call(response)
+}
+
+// Stop stops refreshing the timer and disarms it if the connection is active.
+func (d *DeadMansSwitch) Stop() error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.stop == nil {
+		return nil
+	}
+	close(d.stop)
+	<-d.done
+	d.stop = nil
+	if !d.ws.IsActive() {
+		return nil
+	}
+	pending, err := d.ws.CancelAllOrdersAfter(0)
+	if err != nil {
+		return fmt.Errorf("disarm dead man's switch: %w", err)
+	}
+	if _, err := pending.Wait(); err != nil {
+		return fmt.Errorf("disarm dead man's switch: %w", err)
+	}
+	return nil
+}
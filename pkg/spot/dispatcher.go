@@ -0,0 +1,115 @@
+package spot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// Dispatcher decodes WebSocket v2 messages into typed structs and calls the callback manager of their channel or method.
+// Messages are only decoded if the manager has registered callbacks.
+type Dispatcher struct {
+	OnTicker      *callback.Manager[*TickerUpdate]
+	OnBook        *callback.Manager[*BookUpdate]
+	OnLevel3      *callback.Manager[*Level3Update]
+	OnCandles     *callback.Manager[*CandleUpdate]
+	OnTrades      *callback.Manager[*TradeUpdate]
+	OnInstruments *callback.Manager[*InstrumentUpdate]
+	OnExecutions  *callback.Manager[*ExecutionsUpdate]
+	OnBalances    *callback.Manager[*BalancesUpdate]
+	OnStatus      *callback.Manager[*StatusUpdate]
+	OnHeartbeat   *callback.Manager[*HeartbeatUpdate]
+
+	OnSubscribe   *callback.Manager[*WebSocketResponse[SubscriptionResult]]
+	OnUnsubscribe *callback.Manager[*WebSocketResponse[SubscriptionResult]]
+	OnPong        *callback.Manager[*WebSocketResponse[any]]
+
+	// Called with every method response, including those of trading requests.
+	OnResponse *callback.Manager[*WebSocketResponse[json.RawMessage]]
+
+	// Called with messages that could not be decoded when the dispatcher is embedded in a [WebSocket].
+	OnDecodeError *callback.Manager[error]
+}
+
+// NewDispatcher constructs a [Dispatcher] with empty callback managers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		OnTicker:      callback.NewManager[*TickerUpdate](),
+		OnBook:        callback.NewManager[*BookUpdate](),
+		OnLevel3:      callback.NewManager[*Level3Update](),
+		OnCandles:     callback.NewManager[*CandleUpdate](),
+		OnTrades:      callback.NewManager[*TradeUpdate](),
+		OnInstruments: callback.NewManager[*InstrumentUpdate](),
+		OnExecutions:  callback.NewManager[*ExecutionsUpdate](),
+		OnBalances:    callback.NewManager[*BalancesUpdate](),
+		OnStatus:      callback.NewManager[*StatusUpdate](),
+		OnHeartbeat:   callback.NewManager[*HeartbeatUpdate](),
+		OnSubscribe:   callback.NewManager[*WebSocketResponse[SubscriptionResult]](),
+		OnUnsubscribe: callback.NewManager[*WebSocketResponse[SubscriptionResult]](),
+		OnPong:        callback.NewManager[*WebSocketResponse[any]](),
+		OnResponse:    callback.NewManager[*WebSocketResponse[json.RawMessage]](),
+		OnDecodeError: callback.NewManager[error](),
+	}
+}
+
+// Dispatch decodes a message and passes it to the callback manager of its channel or method.
+// Messages of unknown channels and methods are ignored.
+func (d *Dispatcher) Dispatch(m *kraken.WebSocketMessage) error {
+	data, err := m.Map()
+	if err != nil {
+		return err
+	}
+	if method, ok := data["method"].(string); ok {
+		if err := dispatch(m, d.OnResponse); err != nil {
+			return err
+		}
+		switch method {
+		case "subscribe":
+			return dispatch(m, d.OnSubscribe)
+		case "unsubscribe":
+			return dispatch(m, d.OnUnsubscribe)
+		case "pong":
+			return dispatch(m, d.OnPong)
+		}
+		return nil
+	}
+	channel, _ := data["channel"].(string)
+	switch channel {
+	case "ticker":
+		return dispatch(m, d.OnTicker)
+	case "book":
+		return dispatch(m, d.OnBook)
+	case "level3":
+		return dispatch(m, d.OnLevel3)
+	case "ohlc":
+		return dispatch(m, d.OnCandles)
+	case "trade":
+		return dispatch(m, d.OnTrades)
+	case "instrument":
+		return dispatch(m, d.OnInstruments)
+	case "executions":
+		return dispatch(m, d.OnExecutions)
+	case "balances":
+		return dispatch(m, d.OnBalances)
+	case "status":
+		return dispatch(m, d.OnStatus)
+	case "heartbeat":
+		return dispatch(m, d.OnHeartbeat)
+	}
+	return nil
+}
+
+// dispatch decodes a message into T and calls manager if it has callbacks.
+func dispatch[T any](m *kraken.WebSocketMessage, manager *callback.Manager[*T]) error {
+	if manager.Len() == 0 {
+		return nil
+	}
+	var v T
+	if err := m.JSON(&v); err != nil {
+		return fmt.Errorf("decode %T: %w", v, err)
+	}
+	manager.Call(&v)
+	return nil
+}
@@ -0,0 +1,41 @@
+package spot
+
+import (
+	"testing"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+func TestDispatcher(t *testing.T) {
+	d := NewDispatcher()
+	var books []*BookUpdate
+	var subscriptions []*WebSocketResponse[SubscriptionResult]
+	d.OnBook.Recurring(func(e *callback.Event[*BookUpdate]) {
+		books = append(books, e.Data)
+	})
+	d.OnSubscribe.Recurring(func(e *callback.Event[*WebSocketResponse[SubscriptionResult]]) {
+		subscriptions = append(subscriptions, e.Data)
+	})
+	messages := []string{
+		`{"method":"subscribe","result":{"channel":"book","depth":10,"snapshot":true,"symbol":"BTC/USD"},"success":true,"time_in":"2023-09-25T09:04:31.742599Z","time_out":"2023-09-25T09:04:31.742648Z"}`,
+		`{"channel":"book","type":"update","data":[{"symbol":"BTC/USD","bids":[{"price":26544.2,"qty":0.00031}],"asks":[],"checksum":2439117997,"timestamp":"2023-10-06T17:35:55.440295Z"}]}`,
+		`{"channel":"ticker","type":"update","data":[{"symbol":"BTC/USD","bid":26544.2}]}`,
+		`{"channel":"heartbeat"}`,
+	}
+	for _, message := range messages {
+		if err := d.Dispatch(kraken.NewWebSocketMessage([]byte(message))); err != nil {
+			t.Fatalf("Dispatch(%s): %s", message, err)
+		}
+	}
+	if len(subscriptions) != 1 || subscriptions[0].Result.Depth != 10 || !subscriptions[0].Success {
+		t.Errorf("expected a successful book subscription with depth 10, got %+v", subscriptions)
+	}
+	if len(books) != 1 {
+		t.Fatalf("expected 1 book update, got %d", len(books))
+	}
+	data := books[0].Data[0]
+	if data.Checksum != 2439117997 || data.Bids[0].Price.String() != "26544.2" || data.Bids[0].Qty.String() != "0.00031" {
+		t.Errorf("unexpected book update %+v", data)
+	}
+}
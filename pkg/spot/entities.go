@@ -561,3 +561,76 @@ type EarnAllocation struct {
 	TotalRewarded   EarnAllocationReward `json:"total_rewarded,omitempty"`
 	Payout          EarnAllocationPayout `json:"payout,omitempty"`
 }
+
+type WebSocketAddOrderResult struct {
+	OrderID      string   `json:"order_id,omitempty"`
+	ClOrdID      string   `json:"cl_ord_id,omitempty"`
+	OrderUserref int      `json:"order_userref,omitempty"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+type WebSocketAmendOrderResult struct {
+	AmendID  string   `json:"amend_id,omitempty"`
+	OrderID  string   `json:"order_id,omitempty"`
+	ClOrdID  string   `json:"cl_ord_id,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type WebSocketCancelOrderResult struct {
+	OrderID  string   `json:"order_id,omitempty"`
+	ClOrdID  string   `json:"cl_ord_id,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type WebSocketCancelAllResult struct {
+	Count    int      `json:"count,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type WebSocketEditOrderResult struct {
+	OrderID         string   `json:"order_id,omitempty"`
+	OriginalOrderID string   `json:"original_order_id,omitempty"`
+	Warnings        []string `json:"warnings,omitempty"`
+}
+
+type WebSocketCancelAfterResult struct {
+	CurrentTime string `json:"currentTime,omitempty"`
+	TriggerTime string `json:"triggerTime,omitempty"`
+}
+
+type WebSocketTriggers struct {
+	Reference string           `json:"reference,omitempty"`
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	PriceType string           `json:"price_type,omitempty"`
+}
+
+type WebSocketConditional struct {
+	OrderType        string           `json:"order_type,omitempty"`
+	LimitPrice       *decimal.Decimal `json:"limit_price,omitempty"`
+	LimitPriceType   string           `json:"limit_price_type,omitempty"`
+	TriggerPrice     *decimal.Decimal `json:"trigger_price,omitempty"`
+	TriggerPriceType string           `json:"trigger_price_type,omitempty"`
+}
+
+type WebSocketOrderRequest struct {
+	OrderType      string                `json:"order_type,omitempty"`
+	Side           string                `json:"side,omitempty"`
+	OrderQty       *decimal.Decimal      `json:"order_qty,omitempty"`
+	LimitPrice     *decimal.Decimal      `json:"limit_price,omitempty"`
+	LimitPriceType string                `json:"limit_price_type,omitempty"`
+	Triggers       *WebSocketTriggers    `json:"triggers,omitempty"`
+	TimeInForce    string                `json:"time_in_force,omitempty"`
+	Margin         bool                  `json:"margin,omitempty"`
+	PostOnly       bool                  `json:"post_only,omitempty"`
+	ReduceOnly     bool                  `json:"reduce_only,omitempty"`
+	EffectiveTime  string                `json:"effective_time,omitempty"`
+	ExpireTime     string                `json:"expire_time,omitempty"`
+	ClOrdID        string                `json:"cl_ord_id,omitempty"`
+	OrderUserref   int                   `json:"order_userref,omitempty"`
+	Conditional    *WebSocketConditional `json:"conditional,omitempty"`
+	DisplayQty     *decimal.Decimal      `json:"display_qty,omitempty"`
+	FeePreference  string                `json:"fee_preference,omitempty"`
+	NoMPP          bool                  `json:"no_mpp,omitempty"`
+	StpType        string                `json:"stp_type,omitempty"`
+	CashOrderQty   *decimal.Decimal      `json:"cash_order_qty,omitempty"`
+}
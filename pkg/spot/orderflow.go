@@ -0,0 +1,351 @@
+package spot
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// FlowKind is the classification of a level3 order event.
+type FlowKind string
+
+const (
+	FlowAdd    FlowKind = "add"
+	FlowCancel FlowKind = "cancel"
+	FlowModify FlowKind = "modify"
+
+	// A deleted or reduced order matched with a trade at its price.
+	FlowFill FlowKind = "fill"
+)
+
+// FlowEvent is a classified level3 order event.
+type FlowEvent struct {
+	*OrderEvent
+	Kind FlowKind `json:"kind,omitempty"`
+
+	// Quantity of the order after the event minus before it, which is negative for cancels, fills and reductions.
+	Change *decimal.Decimal `json:"change,omitempty"`
+
+	// Time between the add and the delete of an order, or zero if the order was already in the snapshot.
+	Lifetime time.Duration `json:"lifetime,omitempty"`
+
+	// Whether the event was first classified as a cancel or modify and matched with a later trade.
+	Reclassified bool `json:"reclassified,omitempty"`
+}
+
+// FlowMetrics contains the order flow statistics of a symbol over the rolling window of an [OrderFlow].
+type FlowMetrics struct {
+	Symbol string        `json:"symbol,omitempty"`
+	Window time.Duration `json:"window,omitempty"`
+
+	Adds          int `json:"adds"`
+	Cancels       int `json:"cancels"`
+	Modifications int `json:"modifications"`
+	Fills         int `json:"fills"`
+	Trades        int `json:"trades"`
+
+	// Adds and cancels per second.
+	ArrivalRate float64 `json:"arrivalRate"`
+	CancelRate  float64 `json:"cancelRate"`
+
+	// Number of order events per trade, or zero if there are no trades.
+	OrderToTrade float64 `json:"orderToTrade"`
+
+	// Average lifetime of the orders added and deleted within the window.
+	AverageLifetime time.Duration `json:"averageLifetime,omitempty"`
+
+	// Bid quantity minus ask quantity divided by their sum for each depth of the order flow, from -1 to 1.
+	Imbalance map[int]float64 `json:"imbalance,omitempty"`
+}
+
+// OrderFlow classifies the level3 order events of a [BookManager] and computes rolling order flow metrics per symbol.
+// Cancels and reductions are classified as fills when a trade of the opposite side occurs at the same price within MatchWindow.
+//
+// Register [OrderFlow.HandleTrades] with [Dispatcher.OnTrades] to infer fills.
+type OrderFlow struct {
+	Books *BookManager
+
+	// Duration of the rolling metrics.
+	Window time.Duration
+
+	// Maximum time between an order event and a trade for them to be matched.
+	MatchWindow time.Duration
+
+	// Number of price levels of the imbalance metrics.
+	Depths []int
+
+	// Called when an order event is classified, and again if it is reclassified as a fill.
+	OnFlow *callback.Manager[*FlowEvent]
+
+	symbols map[string]*flowState
+	mux     sync.Mutex
+}
+
+// flowState contains the orders and the events of a symbol within the window.
+type flowState struct {
+	orders  map[string]*flowOrder
+	records []*flowRecord
+	trades  []*flowTrade
+
+	// Whether the last event was an order of a snapshot.
+	snapshot bool
+}
+
+type flowOrder struct {
+	quantity *decimal.Decimal
+	added    time.Time
+}
+
+// flowRecord is a classified event with the removed quantity not matched with trades yet.
+type flowRecord struct {
+	at        time.Time
+	kind      FlowKind
+	event     *FlowEvent
+	unmatched *decimal.Decimal
+}
+
+// flowTrade is a trade with the quantity not matched with order events yet.
+type flowTrade struct {
+	at        time.Time
+	direction book.BookDirection
+	price     *decimal.Decimal
+	unmatched *decimal.Decimal
+}
+
+// NewOrderFlow constructs an [OrderFlow] with a one minute window and imbalance depths of 1, 5 and 10 for the books of the given manager.
+func NewOrderFlow(books *BookManager) *OrderFlow {
+	f := &OrderFlow{
+		Books:       books,
+		Window:      time.Minute,
+		MatchWindow: time.Second,
+		Depths:      []int{1, 5, 10},
+		OnFlow:      callback.NewManager[*FlowEvent](),
+		symbols:     make(map[string]*flowState),
+	}
+	books.OnOrderEvent.Recurring(f.HandleOrderEvent)
+	return f
+}
+
+// state returns the state of a symbol while the order flow is locked, discarding the events outside of the window.
+func (f *OrderFlow) state(symbol string, now time.Time) *flowState {
+	s, ok := f.symbols[symbol]
+	if !ok {
+		s = &flowState{orders: make(map[string]*flowOrder)}
+		f.symbols[symbol] = s
+	}
+	cutoff := now.Add(-f.Window)
+	for len(s.records) > 0 && s.records[0].at.Before(cutoff) {
+		s.records = s.records[1:]
+	}
+	for len(s.trades) > 0 && s.trades[0].at.Before(cutoff) {
+		s.trades = s.trades[1:]
+	}
+	return s
+}
+
+// HandleOrderEvent classifies a level3 order event and matches it with the previous trades.
+// The orders of snapshots are recorded without being classified and replace the previous orders of the symbol.
+func (f *OrderFlow) HandleOrderEvent(e *callback.Event[*OrderEvent]) {
+	event := e.Data
+	now := time.Now()
+	f.mux.Lock()
+	s := f.state(event.Symbol, now)
+	if event.Event == "" && !s.snapshot {
+		clear(s.orders)
+	}
+	s.snapshot = event.Event == ""
+	previous := s.orders[event.OrderID]
+	flow := &FlowEvent{OrderEvent: event}
+	switch {
+	case event.Event == "":
+		if event.Quantity.Sign() > 0 {
+			s.orders[event.OrderID] = &flowOrder{quantity: event.Quantity}
+		}
+		f.mux.Unlock()
+		return
+	case previous == nil && event.Quantity.Sign() > 0:
+		flow.Kind = FlowAdd
+		flow.Change = event.Quantity
+		s.orders[event.OrderID] = &flowOrder{quantity: event.Quantity, added: event.Timestamp}
+	case event.Event == "delete" || event.Quantity.Sign() <= 0:
+		flow.Kind = FlowCancel
+		flow.Change = decimal.NewFromInt64(0)
+		if previous != nil {
+			flow.Change = flow.Change.Sub(previous.quantity)
+			if !previous.added.IsZero() {
+				flow.Lifetime = event.Timestamp.Sub(previous.added)
+			}
+		}
+		delete(s.orders, event.OrderID)
+	default:
+		flow.Kind = FlowModify
+		flow.Change = event.Quantity.Sub(previous.quantity)
+		previous.quantity = event.Quantity
+	}
+	record := &flowRecord{at: now, kind: flow.Kind, event: flow}
+	if flow.Change.Sign() < 0 {
+		record.unmatched = flow.Change.Abs()
+		if f.matchTrades(s, record) {
+			record.kind = FlowFill
+			flow.Kind = FlowFill
+		}
+	}
+	s.records = append(s.records, record)
+	f.mux.Unlock()
+	f.OnFlow.Call(flow)
+}
+
+// HandleTrades records trades and reclassifies the matching cancels and reductions as fills.
+func (f *OrderFlow) HandleTrades(e *callback.Event[*TradeUpdate]) {
+	now := time.Now()
+	var reclassified []*FlowEvent
+	f.mux.Lock()
+	for _, data := range e.Data.Data {
+		if data.Price == nil || data.Qty == nil {
+			continue
+		}
+		s := f.state(strings.ToUpper(data.Symbol), now)
+		trade := &flowTrade{
+			at:        now,
+			direction: book.Ask,
+			price:     data.Price,
+			unmatched: data.Qty,
+		}
+		if data.Side == "sell" {
+			trade.direction = book.Bid
+		}
+		s.trades = append(s.trades, trade)
+		for _, record := range s.records {
+			if !f.matches(record, trade) {
+				continue
+			}
+			f.match(record, trade)
+			if record.kind != FlowFill {
+				record.kind = FlowFill
+				flow := *record.event
+				flow.Kind = FlowFill
+				flow.Reclassified = true
+				reclassified = append(reclassified, &flow)
+			}
+		}
+	}
+	f.mux.Unlock()
+	for _, flow := range reclassified {
+		f.OnFlow.Call(flow)
+	}
+}
+
+// matchTrades matches a record with the previous trades and reports whether any quantity was matched.
+func (f *OrderFlow) matchTrades(s *flowState, record *flowRecord) bool {
+	var matched bool
+	for _, trade := range s.trades {
+		if f.matches(record, trade) {
+			f.match(record, trade)
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matches reports whether a record and a trade have unmatched quantities at the same price and side within the match window.
+func (f *OrderFlow) matches(record *flowRecord, trade *flowTrade) bool {
+	if record.unmatched == nil || record.unmatched.Sign() <= 0 || trade.unmatched.Sign() <= 0 {
+		return false
+	}
+	if elapsed := record.at.Sub(trade.at).Abs(); elapsed > f.MatchWindow {
+		return false
+	}
+	return record.event.Direction == trade.direction && record.event.Price.Cmp(trade.price) == 0
+}
+
+// match deducts the quantity matched between a record and a trade from both.
+func (f *OrderFlow) match(record *flowRecord, trade *flowTrade) {
+	quantity := record.unmatched
+	if trade.unmatched.Cmp(quantity) < 0 {
+		quantity = trade.unmatched
+	}
+	record.unmatched = record.unmatched.Sub(quantity)
+	trade.unmatched = trade.unmatched.Sub(quantity)
+}
+
+// Metrics returns the order flow metrics of a symbol over the window.
+func (f *OrderFlow) Metrics(symbol string) *FlowMetrics {
+	symbol = strings.ToUpper(symbol)
+	metrics := &FlowMetrics{
+		Symbol: symbol,
+		Window: f.Window,
+	}
+	f.mux.Lock()
+	s := f.state(symbol, time.Now())
+	var lifetime time.Duration
+	var lifetimes int
+	for _, record := range s.records {
+		switch record.kind {
+		case FlowAdd:
+			metrics.Adds++
+		case FlowCancel:
+			metrics.Cancels++
+		case FlowModify:
+			metrics.Modifications++
+		case FlowFill:
+			metrics.Fills++
+		}
+		if record.event.Lifetime > 0 {
+			lifetime += record.event.Lifetime
+			lifetimes++
+		}
+	}
+	metrics.Trades = len(s.trades)
+	f.mux.Unlock()
+	if seconds := f.Window.Seconds(); seconds > 0 {
+		metrics.ArrivalRate = float64(metrics.Adds) / seconds
+		metrics.CancelRate = float64(metrics.Cancels) / seconds
+	}
+	if metrics.Trades > 0 {
+		events := metrics.Adds + metrics.Cancels + metrics.Modifications + metrics.Fills
+		metrics.OrderToTrade = float64(events) / float64(metrics.Trades)
+	}
+	if lifetimes > 0 {
+		metrics.AverageLifetime = lifetime / time.Duration(lifetimes)
+	}
+	if b := f.Books.GetBook(symbol); b != nil {
+		metrics.Imbalance = make(map[int]float64)
+		snapshot := b.Snapshot(snapshotDepth(f.Depths))
+		for _, depth := range f.Depths {
+			bids := levelQuantity(snapshot.Bids, depth).Float64()
+			asks := levelQuantity(snapshot.Asks, depth).Float64()
+			if bids+asks > 0 {
+				metrics.Imbalance[depth] = (bids - asks) / (bids + asks)
+			}
+		}
+	}
+	return metrics
+}
+
+// snapshotDepth returns the number of levels per side needed to compute the imbalance at all depths, or 0 for all levels.
+func snapshotDepth(depths []int) int {
+	n := 0
+	for _, depth := range depths {
+		if depth <= 0 {
+			return 0
+		}
+		n = max(n, depth)
+	}
+	return n
+}
+
+// levelQuantity returns the total quantity of up to depth levels from the best price, or all of them if depth is not positive.
+func levelQuantity(levels []*book.LevelSnapshot, depth int) *decimal.Decimal {
+	if depth > 0 && depth < len(levels) {
+		levels = levels[:depth]
+	}
+	quantity := decimal.NewFromInt64(0)
+	for _, level := range levels {
+		quantity = quantity.Add(level.Quantity)
+	}
+	return quantity
+}
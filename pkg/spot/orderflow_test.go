@@ -0,0 +1,101 @@
+package spot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+func TestOrderFlow(t *testing.T) {
+	bm := NewBookManager()
+	flow := NewOrderFlow(bm)
+	var flows []*FlowEvent
+	flow.OnFlow.Recurring(func(e *callback.Event[*FlowEvent]) {
+		flows = append(flows, e.Data)
+	})
+	message := func(symbol string, checksum string) map[string]any {
+		m, err := kraken.NewWebSocketMessage([]byte(`{"symbol":"` + symbol + `","checksum":` + checksum + `,"asks":[],"bids":[` +
+			`{"event":"add","order_id":"a","limit_price":100,"order_qty":2,"timestamp":"2024-01-01T00:00:00Z"}]}`)).Map()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	invalid := bm.CreateBook("ETH/USD", 10)
+	if err := bm.UpdateL3(invalid, message("ETH/USD", "0")); !errors.Is(err, ErrChecksumFailed) {
+		t.Fatalf("expected a checksum failure, got %v", err)
+	}
+	if len(flows) != 0 {
+		t.Fatalf("expected no events from a message failing its checksum, got %+v", flows)
+	}
+	b := bm.CreateBook("BTC/USD", 10)
+	checksum := invalid.L3Checksum("0").LocalChecksum
+	if err := bm.UpdateL3(b, message("BTC/USD", checksum)); err != nil {
+		t.Fatalf("UpdateL3: %s", err)
+	}
+	if len(flows) != 1 || flows[0].Kind != FlowAdd || flows[0].OrderID != "a" {
+		t.Fatalf("expected an add of a, got %+v", flows)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	order := func(event string, id string, direction book.BookDirection, price int64, quantity int64, seconds int) {
+		flow.HandleOrderEvent(&callback.Event[*OrderEvent]{Data: &OrderEvent{
+			Symbol:    "BTC/USD",
+			Event:     event,
+			Direction: direction,
+			OrderID:   id,
+			Price:     decimal.NewFromInt64(price),
+			Quantity:  decimal.NewFromInt64(quantity),
+			Timestamp: start.Add(time.Duration(seconds) * time.Second),
+		}})
+	}
+	trade := func(side string, price int64, quantity int64) {
+		flow.HandleTrades(&callback.Event[*TradeUpdate]{Data: &TradeUpdate{
+			Data: []TradeData{{Symbol: "BTC/USD", Side: side, Price: decimal.NewFromInt64(price), Qty: decimal.NewFromInt64(quantity)}},
+		}})
+	}
+	order("add", "b", book.Bid, 100, 3, 1)
+	order("add", "c", book.Ask, 101, 1, 0)
+	trade("sell", 100, 2)
+	order("delete", "a", book.Bid, 100, 0, 10)
+	order("modify", "b", book.Bid, 100, 1, 11)
+	order("delete", "c", book.Ask, 101, 0, 20)
+	trade("buy", 101, 1)
+	kinds := make([]FlowKind, len(flows))
+	for i, event := range flows {
+		kinds[i] = event.Kind
+	}
+	expected := []FlowKind{FlowAdd, FlowAdd, FlowAdd, FlowFill, FlowModify, FlowCancel, FlowFill}
+	if len(kinds) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, kinds)
+	}
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, kinds)
+		}
+	}
+	if !flows[6].Reclassified || flows[6].OrderID != "c" {
+		t.Errorf("expected c to be reclassified as a fill, got %+v", flows[6])
+	}
+	metrics := flow.Metrics("BTC/USD")
+	if metrics.Adds != 3 || metrics.Fills != 2 || metrics.Modifications != 1 || metrics.Cancels != 0 || metrics.Trades != 2 {
+		t.Errorf("expected 3 adds, 2 fills, 1 modification and 2 trades, got %+v", metrics)
+	}
+	if metrics.OrderToTrade != 3 || metrics.AverageLifetime != 15*time.Second {
+		t.Errorf("expected an order to trade ratio of 3 and an average lifetime of 15s, got %+v", metrics)
+	}
+	if metrics.Imbalance[1] != 1 {
+		t.Errorf("expected an imbalance of 1 at depth 1, got %v", metrics.Imbalance)
+	}
+	flows = nil
+	order("", "d", book.Bid, 99, 1, 30)
+	order("", "e", book.Bid, 99, 1, 30)
+	order("add", "b", book.Bid, 100, 1, 31)
+	if len(flows) != 1 || flows[0].Kind != FlowAdd {
+		t.Errorf("expected b to be added again after a snapshot, got %+v", flows)
+	}
+}
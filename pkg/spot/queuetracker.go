@@ -0,0 +1,248 @@
+package spot
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+// OrderPosition is the queue position of a resting order of the user in a level3 book.
+type OrderPosition struct {
+	Symbol string `json:"symbol,omitempty"`
+	*book.QueuePosition
+
+	// Quantity ahead that was filled or cancelled since the previous position.
+	Advanced *decimal.Decimal `json:"advanced,omitempty"`
+
+	// Estimated probability of the order being filled within the horizon of the tracker, from 0 to 1.
+	// The quantity ahead is assumed to be consumed by fills and cancels arriving as a Poisson process at the rate and with the average size observed since the order was tracked.
+	FillProbability float64 `json:"fillProbability"`
+
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// QueueTracker reports the queue positions of the resting limit orders of the user by joining the executions channel with the level3 books of a [BookManager].
+//
+// Register [QueueTracker.HandleExecutions] with [Dispatcher.OnExecutions] and feed the level3 messages to the book manager.
+type QueueTracker struct {
+	Books *BookManager
+
+	// Period of the fill probability estimate.
+	Horizon time.Duration
+
+	// Called when the position of an order changes.
+	OnPosition *callback.Manager[*OrderPosition]
+
+	// Called with the last known position when an order stops resting, because it was filled, cancelled or expired.
+	OnRemoved *callback.Manager[*OrderPosition]
+
+	orders map[string]*queuedOrder
+	hooked map[*book.Book]bool
+	mux    sync.Mutex
+}
+
+// queuedOrder is a resting order with the quantity ahead of it consumed since it was tracked.
+type queuedOrder struct {
+	symbol     string
+	direction  book.BookDirection
+	price      *decimal.Decimal
+	since      time.Time
+	depleted   *decimal.Decimal
+	depletions int
+	position   *OrderPosition
+}
+
+// NewQueueTracker constructs a [QueueTracker] with a one minute horizon for the books of the given manager.
+func NewQueueTracker(books *BookManager) *QueueTracker {
+	t := &QueueTracker{
+		Books:      books,
+		Horizon:    time.Minute,
+		OnPosition: callback.NewManager[*OrderPosition](),
+		OnRemoved:  callback.NewManager[*OrderPosition](),
+		orders:     make(map[string]*queuedOrder),
+		hooked:     make(map[*book.Book]bool),
+	}
+	books.OnCreateBook.Recurring(func(e *callback.Event[*book.Book]) {
+		t.hook(e.Data)
+	})
+	return t
+}
+
+// HandleExecutions tracks the limit orders resting in the book and stops tracking the others.
+func (t *QueueTracker) HandleExecutions(e *callback.Event[*ExecutionsUpdate]) {
+	for _, execution := range e.Data.Data {
+		if slices.Contains([]string{"filled", "canceled", "expired"}, execution.OrderStatus) {
+			t.Untrack(execution.OrderID)
+			continue
+		}
+		if execution.OrderType != "" && execution.OrderType != "limit" || execution.LimitPrice == nil || execution.Symbol == "" || execution.Side == "" {
+			continue
+		}
+		direction := book.BookDirection(book.Bid)
+		if execution.Side == "sell" {
+			direction = book.Ask
+		}
+		t.Track(execution.Symbol, direction, execution.LimitPrice, execution.OrderID)
+	}
+}
+
+// Track starts reporting the queue position of an order, or updates its price if it is already tracked.
+func (t *QueueTracker) Track(symbol string, direction book.BookDirection, price *decimal.Decimal, orderID string) {
+	symbol = strings.ToUpper(symbol)
+	t.mux.Lock()
+	order, ok := t.orders[orderID]
+	if !ok || order.price.Cmp(price) != 0 {
+		order = &queuedOrder{
+			symbol:    symbol,
+			direction: direction,
+			price:     price,
+			since:     time.Now(),
+			depleted:  decimal.NewFromInt64(0),
+		}
+		t.orders[orderID] = order
+	}
+	t.mux.Unlock()
+	if b := t.Books.GetBook(symbol); b != nil {
+		t.hook(b)
+		t.refresh(b, orderID)
+	}
+}
+
+// Untrack stops reporting the queue position of an order and calls OnRemoved if it was tracked.
+func (t *QueueTracker) Untrack(orderID string) {
+	t.mux.Lock()
+	order, ok := t.orders[orderID]
+	delete(t.orders, orderID)
+	t.mux.Unlock()
+	if ok {
+		position := order.position
+		if position == nil {
+			position = &OrderPosition{
+				Symbol: order.symbol,
+				QueuePosition: &book.QueuePosition{
+					OrderID:   orderID,
+					Direction: order.direction,
+					Price:     order.price,
+				},
+				Timestamp: time.Now(),
+			}
+		}
+		t.OnRemoved.Call(position)
+	}
+}
+
+// Position returns the last position of a tracked order, or nil if it is unknown.
+func (t *QueueTracker) Position(orderID string) *OrderPosition {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if order, ok := t.orders[orderID]; ok {
+		return order.position
+	}
+	return nil
+}
+
+// Positions returns the last positions of all tracked orders found in the books.
+func (t *QueueTracker) Positions() []*OrderPosition {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	var positions []*OrderPosition
+	for _, order := range t.orders {
+		if order.position != nil {
+			positions = append(positions, order.position)
+		}
+	}
+	return positions
+}
+
+// hook refreshes the positions of the orders at the price levels updated in a book.
+func (t *QueueTracker) hook(b *book.Book) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.hooked[b] {
+		return
+	}
+	t.hooked[b] = true
+	b.OnUpdated.Recurring(func(e *callback.Event[*book.UpdateOptions]) {
+		t.mux.Lock()
+		var ids []string
+		for id, order := range t.orders {
+			if order.symbol == b.Name && order.direction == e.Data.Direction && order.price.Cmp(e.Data.Price) == 0 {
+				ids = append(ids, id)
+			}
+		}
+		t.mux.Unlock()
+		for _, id := range ids {
+			t.refresh(b, id)
+		}
+	})
+}
+
+// refresh recomputes the position of an order and calls OnPosition if it changed.
+func (t *QueueTracker) refresh(b *book.Book, orderID string) {
+	t.mux.Lock()
+	order, ok := t.orders[orderID]
+	t.mux.Unlock()
+	if !ok {
+		return
+	}
+	queue := b.QueuePosition(order.direction, order.price, orderID)
+	if queue == nil {
+		return
+	}
+	now := time.Now()
+	t.mux.Lock()
+	if t.orders[orderID] != order {
+		t.mux.Unlock()
+		return
+	}
+	previous := order.position
+	advanced := decimal.NewFromInt64(0)
+	if previous != nil {
+		if previous.Index == queue.Index && previous.Ahead.Cmp(queue.Ahead) == 0 && previous.Quantity.Cmp(queue.Quantity) == 0 {
+			t.mux.Unlock()
+			return
+		}
+		if previous.Ahead.Cmp(queue.Ahead) > 0 {
+			advanced = previous.Ahead.Sub(queue.Ahead)
+			order.depleted = order.depleted.Add(advanced)
+			order.depletions++
+		}
+	}
+	position := &OrderPosition{
+		Symbol:          order.symbol,
+		QueuePosition:   queue,
+		Advanced:        advanced,
+		FillProbability: fillProbability(order.depleted, order.depletions, now.Sub(order.since), t.Horizon, queue.Ahead.Add(queue.Quantity)),
+		Timestamp:       now,
+	}
+	order.position = position
+	t.mux.Unlock()
+	t.OnPosition.Call(position)
+}
+
+// fillProbability estimates the probability of consuming the remaining quantity within the horizon.
+// The depletions of the quantity ahead are counted as a Poisson process, so the probability is the one of at least as many depletions of the average size as needed to consume the remaining quantity.
+func fillProbability(depleted *decimal.Decimal, depletions int, elapsed time.Duration, horizon time.Duration, remaining *decimal.Decimal) float64 {
+	if depletions == 0 || elapsed <= 0 || remaining.Sign() <= 0 {
+		return 0
+	}
+	size := depleted.Float64() / float64(depletions)
+	needed := math.Ceil(remaining.Float64() / size)
+	mean := float64(depletions) * horizon.Seconds() / elapsed.Seconds()
+	if needed > mean+10*math.Sqrt(mean)+10 {
+		return 0
+	}
+	// Probability of fewer depletions than needed, with the terms computed as logarithms to avoid underflows.
+	below := 0.0
+	for i := 0.0; i < needed; i++ {
+		factorial, _ := math.Lgamma(i + 1)
+		below += math.Exp(i*math.Log(mean) - mean - factorial)
+	}
+	return min(max(1-below, 0), 1)
+}
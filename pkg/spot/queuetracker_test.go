@@ -0,0 +1,89 @@
+package spot
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/book"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+)
+
+func TestQueueTracker(t *testing.T) {
+	bm := NewBookManager()
+	tracker := NewQueueTracker(bm)
+	tracker.Horizon = time.Hour
+	var positions, removed []*OrderPosition
+	tracker.OnPosition.Recurring(func(e *callback.Event[*OrderPosition]) {
+		positions = append(positions, e.Data)
+	})
+	tracker.OnRemoved.Recurring(func(e *callback.Event[*OrderPosition]) {
+		removed = append(removed, e.Data)
+	})
+	b := bm.CreateBook("BTC/USD", 10)
+	order := func(id string, quantity int64, second int64) {
+		b.Update(&book.UpdateOptions{
+			Direction: book.Bid,
+			ID:        id,
+			Price:     decimal.NewFromInt64(100),
+			Quantity:  decimal.NewFromInt64(quantity),
+			Timestamp: time.Unix(second, 0),
+		})
+	}
+	order("a", 2, 1)
+	order("b", 3, 2)
+	tracker.HandleExecutions(&callback.Event[*ExecutionsUpdate]{Data: &ExecutionsUpdate{
+		Data: []ExecutionData{{
+			ExecType:    "new",
+			OrderID:     "own",
+			Symbol:      "BTC/USD",
+			Side:        "buy",
+			OrderType:   "limit",
+			OrderStatus: "new",
+			LimitPrice:  decimal.NewFromInt64(100),
+		}},
+	}})
+	if len(positions) != 0 {
+		t.Errorf("expected no position before the order is in the book, got %d", len(positions))
+	}
+	order("own", 1, 3)
+	order("c", 4, 4)
+	if len(positions) != 1 || positions[0].Index != 2 || positions[0].Ahead.Int64() != 5 {
+		t.Fatalf("expected one position behind 2 orders of 5, got %+v", positions)
+	}
+	order("a", 0, 5)
+	last := tracker.Position("own")
+	if len(positions) != 2 || last.Index != 1 || last.Ahead.Int64() != 3 || last.Advanced.Int64() != 2 {
+		t.Errorf("expected the order to advance by 2 to index 1, got %+v", last)
+	}
+	if last.FillProbability <= 0 || last.FillProbability > 1 {
+		t.Errorf("expected a fill probability in (0, 1], got %f", last.FillProbability)
+	}
+	tracker.HandleExecutions(&callback.Event[*ExecutionsUpdate]{Data: &ExecutionsUpdate{
+		Data: []ExecutionData{{ExecType: "canceled", OrderID: "own", OrderStatus: "canceled"}},
+	}})
+	if len(removed) != 1 || removed[0].OrderID != "own" || tracker.Position("own") != nil {
+		t.Errorf("expected the order to be removed, got %+v", removed)
+	}
+}
+
+func TestFillProbability(t *testing.T) {
+	tests := []struct {
+		depleted   int64
+		depletions int
+		remaining  int64
+		expected   float64
+	}{
+		{2, 1, 2, 1 - math.Exp(-1)},
+		{2, 1, 4, 1 - 2*math.Exp(-1)},
+		{0, 0, 1, 0},
+		{2000, 1000, 2200, 0.0009},
+	}
+	for _, test := range tests {
+		p := fillProbability(decimal.NewFromInt64(test.depleted), test.depletions, time.Minute, time.Minute, decimal.NewFromInt64(test.remaining))
+		if math.Abs(p-test.expected) > 0.0005 {
+			t.Errorf("expected %f for %+v, got %f", test.expected, test, p)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package spot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/internal/helper"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// Tier contains the rate limit parameters of an account verification level.
+//
+// https://docs.kraken.com/api/docs/guides/spot-rest-ratelimits
+//
+// https://docs.kraken.com/api/docs/guides/spot-ratelimits
+type Tier struct {
+	Name              string  `json:"name,omitempty"`
+	MaxCounter        float64 `json:"maxCounter,omitempty"`
+	DecayRate         float64 `json:"decayRate,omitempty"`
+	MaxTradingCounter float64 `json:"maxTradingCounter,omitempty"`
+	TradingDecayRate  float64 `json:"tradingDecayRate,omitempty"`
+}
+
+var (
+	TierStarter      = Tier{Name: "starter", MaxCounter: 15, DecayRate: 0.33, MaxTradingCounter: 60, TradingDecayRate: 1}
+	TierIntermediate = Tier{Name: "intermediate", MaxCounter: 20, DecayRate: 0.5, MaxTradingCounter: 125, TradingDecayRate: 2.34}
+	TierPro          = Tier{Name: "pro", MaxCounter: 20, DecayRate: 1, MaxTradingCounter: 180, TradingDecayRate: 3.75}
+)
+
+// Endpoints that increase the call counter by more than 1.
+var restCosts = map[string]float64{
+	"Ledgers":       2,
+	"QueryLedgers":  2,
+	"TradesHistory": 2,
+	"QueryTrades":   2,
+}
+
+// Endpoints that are rate limited by the matching engine per pair instead of the call counter.
+var tradingEndpoints = map[string]bool{
+	"AddOrder":             true,
+	"AddOrderBatch":        true,
+	"AmendOrder":           true,
+	"EditOrder":            true,
+	"CancelOrder":          true,
+	"CancelOrderBatch":     true,
+	"CancelAll":            true,
+	"CancelAllOrdersAfter": true,
+}
+
+// Orders older than this no longer incur penalties when cancelled or modified.
+const penaltyHorizon = 300 * time.Second
+
+// CancelPenalty returns the increase of the trading counter when cancelling an order of the given age.
+func CancelPenalty(age time.Duration) float64 {
+	switch {
+	case age < 5*time.Second:
+		return 8
+	case age < 10*time.Second:
+		return 6
+	case age < 15*time.Second:
+		return 5
+	case age < 45*time.Second:
+		return 4
+	case age < 90*time.Second:
+		return 2
+	case age < penaltyHorizon:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AmendPenalty returns the increase of the trading counter when amending an order of the given age.
+func AmendPenalty(age time.Duration) float64 {
+	switch {
+	case age < 5*time.Second:
+		return 3
+	case age < 10*time.Second:
+		return 2
+	case age < 45*time.Second:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// EditPenalty returns the increase of the trading counter when editing an order of the given age.
+func EditPenalty(age time.Duration) float64 {
+	switch {
+	case age < 5*time.Second:
+		return 6
+	case age < 10*time.Second:
+		return 5
+	case age < 15*time.Second:
+		return 4
+	case age < 45*time.Second:
+		return 2
+	case age < 90*time.Second:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type trackedOrder struct {
+	pair   string
+	placed time.Time
+}
+
+// RateLimiter models the call counter and the per-pair trading counters of the Spot REST API.
+//
+// Order ages are tracked from the responses of AddOrder and AddOrderBatch to estimate cancel and amend penalties.
+type RateLimiter struct {
+	Tier    Tier
+	Mode    kraken.LimitMode
+	Counter *kraken.DecayCounter
+	trading map[string]*kraken.DecayCounter
+	orders  map[string]*trackedOrder
+	mux     sync.Mutex
+}
+
+// NewRateLimiter constructs a [RateLimiter] for the given account tier.
+func NewRateLimiter(tier Tier) *RateLimiter {
+	return &RateLimiter{
+		Tier:    tier,
+		Mode:    kraken.BlockMode,
+		Counter: kraken.NewDecayCounter(tier.MaxCounter, tier.DecayRate),
+		trading: make(map[string]*kraken.DecayCounter),
+		orders:  make(map[string]*trackedOrder),
+	}
+}
+
+// Cost returns the increase of the call counter for the given endpoint name, e.g. "Ledgers".
+func (l *RateLimiter) Cost(endpoint string) float64 {
+	if tradingEndpoints[endpoint] {
+		return 0
+	}
+	if cost, ok := restCosts[endpoint]; ok {
+		return cost
+	}
+	return 1
+}
+
+// TradingCounter returns the matching engine counter of a pair.
+func (l *RateLimiter) TradingCounter(pair string) *kraken.DecayCounter {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	pair = strings.ToUpper(pair)
+	counter, ok := l.trading[pair]
+	if !ok {
+		counter = kraken.NewDecayCounter(l.Tier.MaxTradingCounter, l.Tier.TradingDecayRate)
+		l.trading[pair] = counter
+	}
+	return counter
+}
+
+// TrackOrder records the placement time of an order under all of its identifiers to calculate penalties.
+func (l *RateLimiter) TrackOrder(pair string, placed time.Time, ids ...string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	for k, order := range l.orders {
+		if time.Since(order.placed) > penaltyHorizon {
+			delete(l.orders, k)
+		}
+	}
+	order := &trackedOrder{pair: strings.ToUpper(pair), placed: placed}
+	for _, id := range ids {
+		if id != "" {
+			l.orders[id] = order
+		}
+	}
+}
+
+func (l *RateLimiter) lookupOrder(ids ...string) *trackedOrder {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	for _, id := range ids {
+		if order, ok := l.orders[id]; ok {
+			return order
+		}
+	}
+	return nil
+}
+
+// untrackOrders removes the orders referenced by ids and returns them without duplicates.
+func (l *RateLimiter) untrackOrders(ids ...string) []*trackedOrder {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	removed := make(map[*trackedOrder]bool)
+	for _, id := range ids {
+		if order, ok := l.orders[id]; ok {
+			removed[order] = true
+		}
+	}
+	var orders []*trackedOrder
+	for id, order := range l.orders {
+		if removed[order] {
+			delete(l.orders, id)
+		}
+	}
+	for order := range removed {
+		orders = append(orders, order)
+	}
+	return orders
+}
+
+// RateLimiterState is a snapshot of a [RateLimiter].
+type RateLimiterState struct {
+	Tier          Tier                                `json:"tier"`
+	Counter       kraken.DecayCounterState            `json:"counter"`
+	Trading       map[string]kraken.DecayCounterState `json:"trading"`
+	TrackedOrders int                                 `json:"trackedOrders"`
+}
+
+// State returns a snapshot of all counters.
+func (l *RateLimiter) State() *RateLimiterState {
+	l.mux.Lock()
+	trading := make(map[string]*kraken.DecayCounter, len(l.trading))
+	for pair, counter := range l.trading {
+		trading[pair] = counter
+	}
+	trackedOrders := len(l.orders)
+	l.mux.Unlock()
+	state := &RateLimiterState{
+		Tier:          l.Tier,
+		Counter:       l.Counter.State(),
+		Trading:       make(map[string]kraken.DecayCounterState, len(trading)),
+		TrackedOrders: trackedOrders,
+	}
+	for pair, counter := range trading {
+		state.Trading[pair] = counter.State()
+	}
+	return state
+}
+
+// Wrap returns a [kraken.ExecutorFunction] that reserves capacity on the counters before calling next.
+func (l *RateLimiter) Wrap(next kraken.ExecutorFunction) kraken.ExecutorFunction {
+	return func(request *http.Request) (*http.Response, error) {
+		endpoint := path.Base(request.URL.Path)
+		if !strings.Contains(request.URL.Path, "/private/") {
+			return next(request)
+		}
+		if !tradingEndpoints[endpoint] {
+			if err := l.Counter.Reserve(request.Context(), l.Cost(endpoint), l.Mode); err != nil {
+				return nil, err
+			}
+			return next(request)
+		}
+		return l.doTrading(request, endpoint, next)
+	}
+}
+
+func (l *RateLimiter) doTrading(request *http.Request, endpoint string, next kraken.ExecutorFunction) (*http.Response, error) {
+	params := requestParams(request)
+	pair, _ := params["pair"].(string)
+	ids := orderIDs(params)
+	now := time.Now()
+	var penalty float64
+	order := l.lookupOrder(ids...)
+	if order != nil {
+		pair = order.pair
+	}
+	switch endpoint {
+	case "AddOrder":
+		penalty = 1
+	case "AddOrderBatch":
+		orders, _ := params["orders"].([]any)
+		penalty = float64(len(orders))
+	case "AmendOrder":
+		if order != nil {
+			penalty = 1 + AmendPenalty(now.Sub(order.placed))
+		}
+	case "EditOrder":
+		penalty = 1
+		if order != nil {
+			penalty += EditPenalty(now.Sub(order.placed))
+		}
+	}
+	if penalty > 0 && pair != "" {
+		if err := l.TradingCounter(pair).Reserve(request.Context(), penalty, l.Mode); err != nil {
+			return nil, err
+		}
+	}
+	response, err := next(request)
+	if err != nil || response == nil || response.StatusCode != http.StatusOK {
+		return response, err
+	}
+	body, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return response, nil
+	}
+	var result Response[map[string]any]
+	if json.Unmarshal(body, &result) != nil || len(result.Error) > 0 {
+		return response, nil
+	}
+	switch endpoint {
+	case "AddOrder":
+		l.TrackOrder(pair, now, append(orderIDs(params), orderIDs(result.Result)...)...)
+	case "AddOrderBatch":
+		requested, _ := params["orders"].([]any)
+		placed, _ := result.Result["orders"].([]any)
+		for i, entry := range placed {
+			entry, _ := entry.(map[string]any)
+			ids := orderIDs(entry)
+			if i < len(requested) {
+				if order, ok := requested[i].(map[string]any); ok {
+					ids = append(ids, orderIDs(order)...)
+				}
+			}
+			l.TrackOrder(pair, now, ids...)
+		}
+	case "CancelOrder", "CancelOrderBatch":
+		for _, order := range l.untrackOrders(ids...) {
+			l.TradingCounter(order.pair).Add(CancelPenalty(now.Sub(order.placed)))
+		}
+	case "CancelAll":
+		l.mux.Lock()
+		orders := make(map[*trackedOrder]bool)
+		for _, order := range l.orders {
+			orders[order] = true
+		}
+		l.orders = make(map[string]*trackedOrder)
+		l.mux.Unlock()
+		for order := range orders {
+			l.TradingCounter(order.pair).Add(CancelPenalty(now.Sub(order.placed)))
+		}
+	}
+	return response, nil
+}
+
+// requestParams decodes the body of a request into a map.
+func requestParams(request *http.Request) map[string]any {
+	if request.GetBody == nil {
+		return map[string]any{}
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return map[string]any{}
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return map[string]any{}
+	}
+	mediaType, _, _ := strings.Cut(request.Header.Get("Content-Type"), ";")
+	params, err := helper.Unmarshal(strings.ToLower(mediaType), data)
+	if err != nil {
+		return map[string]any{}
+	}
+	return params
+}
+
+// orderIDs collects the order identifiers from the `txid` and `cl_ord_id` fields.
+func orderIDs(m map[string]any) (ids []string) {
+	for _, key := range []string{"txid", "cl_ord_id"} {
+		switch v := m[key].(type) {
+		case string:
+			ids = append(ids, v)
+		case []any:
+			for _, id := range v {
+				ids = append(ids, fmt.Sprint(id))
+			}
+		}
+	}
+	if orders, ok := m["orders"].([]any); ok {
+		for _, order := range orders {
+			switch v := order.(type) {
+			case string:
+				ids = append(ids, v)
+			case map[string]any:
+				ids = append(ids, orderIDs(v)...)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,140 @@
+package spot
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// rateLimitRequest constructs a private request with a JSON body.
+func rateLimitRequest(t *testing.T, endpoint string, body string) *http.Request {
+	request, err := http.NewRequest("POST", "https://api.kraken.com/0/private/"+endpoint, bytes.NewReader([]byte(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+// rateLimitExecutor answers each endpoint with a successful response and counts the calls.
+func rateLimitExecutor(calls *int) kraken.ExecutorFunction {
+	return func(request *http.Request) (*http.Response, error) {
+		*calls++
+		result := `{}`
+		switch path.Base(request.URL.Path) {
+		case "AddOrder":
+			result = `{"txid":["ONEW"]}`
+		case "AddOrderBatch":
+			result = `{"orders":[{"txid":"OB1"},{"txid":"OB2"},{"txid":"OB3"}]}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"error":[],"result":` + result + `}`)),
+			Request:    request,
+		}, nil
+	}
+}
+
+func TestRateLimiterTiers(t *testing.T) {
+	for _, tier := range []Tier{TierStarter, TierIntermediate, TierPro} {
+		if tier.MaxCounter <= 0 || tier.DecayRate <= 0 || tier.MaxTradingCounter <= 0 || tier.TradingDecayRate <= 0 {
+			t.Errorf("expected positive limits for %s, got %+v", tier.Name, tier)
+		}
+	}
+	if TierStarter.MaxCounter != 15 || TierPro.MaxTradingCounter != 180 || TierIntermediate.TradingDecayRate != 2.34 {
+		t.Errorf("expected the documented tier values")
+	}
+	penalties := []struct {
+		penalty  func(time.Duration) float64
+		age      time.Duration
+		expected float64
+	}{
+		{CancelPenalty, 2 * time.Second, 8},
+		{CancelPenalty, 60 * time.Second, 2},
+		{CancelPenalty, 301 * time.Second, 0},
+		{AmendPenalty, 7 * time.Second, 2},
+		{AmendPenalty, 50 * time.Second, 0},
+		{EditPenalty, 20 * time.Second, 2},
+	}
+	for i, test := range penalties {
+		if penalty := test.penalty(test.age); penalty != test.expected {
+			t.Errorf("penalty %d: expected %g at %s, got %g", i, test.expected, test.age, penalty)
+		}
+	}
+}
+
+func TestRateLimiterTrading(t *testing.T) {
+	limiter := NewRateLimiter(Tier{MaxCounter: 10, DecayRate: 1e-6, MaxTradingCounter: 100, TradingDecayRate: 1e-6})
+	var calls int
+	execute := limiter.Wrap(rateLimitExecutor(&calls))
+	counter := limiter.TradingCounter("XBTUSD")
+	expect := func(name string, expected float64) {
+		t.Helper()
+		if value := counter.Value(); math.Abs(value-expected) > 0.01 {
+			t.Errorf("%s: expected a trading counter of %g, got %g", name, expected, value)
+		}
+	}
+	if _, err := execute(rateLimitRequest(t, "AddOrder", `{"pair":"XBTUSD","cl_ord_id":"mine"}`)); err != nil {
+		t.Fatalf("AddOrder: %s", err)
+	}
+	expect("AddOrder", 1)
+	if _, err := execute(rateLimitRequest(t, "CancelOrder", `{"txid":"mine"}`)); err != nil {
+		t.Fatalf("CancelOrder: %s", err)
+	}
+	expect("CancelOrder", 1+CancelPenalty(0))
+	if _, err := execute(rateLimitRequest(t, "AddOrderBatch", `{"pair":"XBTUSD","orders":[{},{},{}]}`)); err != nil {
+		t.Fatalf("AddOrderBatch: %s", err)
+	}
+	expect("AddOrderBatch", 12)
+	limiter.TrackOrder("XBTUSD", time.Now().Add(-7*time.Second), "OOLD")
+	if _, err := execute(rateLimitRequest(t, "AmendOrder", `{"txid":"OOLD"}`)); err != nil {
+		t.Fatalf("AmendOrder: %s", err)
+	}
+	expect("AmendOrder", 15)
+	if _, err := execute(rateLimitRequest(t, "CancelAll", `{}`)); err != nil {
+		t.Fatalf("CancelAll: %s", err)
+	}
+	if state := limiter.State(); state.TrackedOrders != 0 || state.Counter.Value != 0 {
+		t.Errorf("expected no tracked orders and no call counter cost, got %+v", state)
+	}
+	if calls != 5 {
+		t.Errorf("expected 5 calls, got %d", calls)
+	}
+}
+
+func TestRateLimiterWrap(t *testing.T) {
+	limiter := NewRateLimiter(Tier{MaxCounter: 3, DecayRate: 1e-6, MaxTradingCounter: 100, TradingDecayRate: 1e-6})
+	limiter.Mode = kraken.FailFastMode
+	var calls int
+	execute := limiter.Wrap(rateLimitExecutor(&calls))
+	if _, err := execute(rateLimitRequest(t, "Ledgers", `{}`)); err != nil {
+		t.Fatalf("Ledgers: %s", err)
+	}
+	if _, err := execute(rateLimitRequest(t, "Balance", `{}`)); err != nil {
+		t.Fatalf("Balance: %s", err)
+	}
+	if _, err := execute(rateLimitRequest(t, "Balance", `{}`)); !errors.Is(err, kraken.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited once the budget is used, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the limited request not to be sent, got %d calls", calls)
+	}
+	limiter = NewRateLimiter(Tier{MaxCounter: 1, DecayRate: 100})
+	execute = limiter.Wrap(rateLimitExecutor(&calls))
+	limiter.Counter.Add(1)
+	started := time.Now()
+	if _, err := execute(rateLimitRequest(t, "Balance", `{}`)); err != nil {
+		t.Fatalf("Balance: %s", err)
+	}
+	if elapsed := time.Since(started); elapsed < 5*time.Millisecond {
+		t.Errorf("expected the request to wait for the counter to decay, returned after %s", elapsed)
+	}
+}
@@ -1,13 +1,17 @@
 package spot
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"maps"
+	"net/http"
+	"path"
 	"reflect"
+	"strings"
 
 	"github.com/krakenfx/api-go/v2/internal/helper"
 	"github.com/krakenfx/api-go/v2/pkg/decimal"
@@ -22,7 +26,10 @@ type REST struct {
 	PrivateKey string
 	BaseURL    string
 	UserAgent  string
+	Client     *http.Client
 	Executor   kraken.ExecutorFunction
+	Limiter    *RateLimiter
+	Retry      *kraken.RetryPolicy
 }
 
 // REST constructs a new [REST] struct with default values.
@@ -32,12 +39,14 @@ func NewREST() *REST {
 	return &REST{
 		Nonce:   kraken.NewEpochCounter().Get,
 		BaseURL: "https://api.kraken.com",
+		Client:  kraken.DefaultClient,
 	}
 }
 
 // NewRequest creates a [kraken.Request] with the parameters specified in [REST].
 func (r *REST) NewRequest(opts RequestOptions) (*kraken.Request, error) {
-	return NewRequest(RequestOptions{
+	req, err := NewRequest(RequestOptions{
+		Context:     opts.Context,
 		Auth:        opts.Auth,
 		Version:     opts.Version,
 		PublicKey:   r.PublicKey,
@@ -52,23 +61,129 @@ func (r *REST) NewRequest(opts RequestOptions) (*kraken.Request, error) {
 		Body:        opts.Body,
 		ContentType: opts.ContentType,
 		UserAgent:   opts.UserAgent,
-		Executor:    r.Executor,
+		Executor:    r.executor(),
 	})
+	if err == nil && r.Limiter != nil {
+		req.Executor = r.Limiter.Wrap(req.Executor)
+	}
+	return req, err
+}
+
+// executor returns [REST.Executor] or the Do method of [REST.Client] if it is not set.
+func (r *REST) executor() kraken.ExecutorFunction {
+	if r.Executor == nil && r.Client != nil {
+		return r.Client.Do
+	}
+	return r.Executor
+}
+
+// GetRetryPolicy implements [kraken.RetryPolicyProvider].
+func (r *REST) GetRetryPolicy() *kraken.RetryPolicy {
+	return r.Retry
 }
 
 type Requestor interface {
 	NewRequest(RequestOptions) (*kraken.Request, error)
 }
 
+// Endpoints whose repeated submission creates duplicate orders or transfers.
+var nonIdempotentEndpoints = map[string]bool{
+	"AddOrder":         true,
+	"AddOrderBatch":    true,
+	"EditOrder":        true,
+	"Withdraw":         true,
+	"WalletTransfer":   true,
+	"AccountTransfer":  true,
+	"CreateSubaccount": true,
+	"CreateUser":       true,
+	"VerifyUser":       true,
+	"Allocate":         true,
+	"Deallocate":       true,
+}
+
+// IsIdempotent reports whether the request can be resubmitted when the outcome of a previous attempt is unknown.
+//
+// Order placement is only considered idempotent if every order has a `cl_ord_id` to reconcile duplicates with.
+func IsIdempotent(opts RequestOptions) bool {
+	segments, err := helper.StringSlice(opts.Path)
+	if err != nil || len(segments) == 0 {
+		return false
+	}
+	endpoint := path.Base(strings.Join(segments, "/"))
+	if !nonIdempotentEndpoints[endpoint] {
+		return true
+	}
+	body, err := bodyMap(opts.Body)
+	if err != nil {
+		return false
+	}
+	switch endpoint {
+	case "AddOrder":
+		return body["cl_ord_id"] != nil && body["cl_ord_id"] != ""
+	case "AddOrderBatch":
+		orders, ok := body["orders"].([]any)
+		if !ok || len(orders) == 0 {
+			return false
+		}
+		for _, order := range orders {
+			order, err := bodyMap(order)
+			if err != nil || order["cl_ord_id"] == nil || order["cl_ord_id"] == "" {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// bodyMap converts a request body into map[string]any through its JSON representation.
+func bodyMap(body any) (map[string]any, error) {
+	m := make(map[string]any)
+	if body == nil {
+		return m, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("json unmarshal: %w", err)
+	}
+	return m, nil
+}
+
 // Call creates a request, checks for errors, and returns a generic response.
+//
+// If r implements [kraken.RetryPolicyProvider], failed calls are retried with a newly signed request per attempt.
+// See [IsIdempotent] for the requests that are retried when the outcome is unknown.
 func Call[T any](r Requestor, opts RequestOptions) (resp *Response[T], err error) {
+	provider, ok := r.(kraken.RetryPolicyProvider)
+	if !ok || provider.GetRetryPolicy() == nil {
+		return call[T](r, opts)
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	err = provider.GetRetryPolicy().Do(ctx, IsIdempotent(opts), func() (*kraken.Response, error) {
+		resp, err = call[T](r, opts)
+		if resp == nil {
+			return nil, err
+		}
+		return resp.Http, err
+	})
+	return resp, err
+}
+
+func call[T any](r Requestor, opts RequestOptions) (resp *Response[T], err error) {
 	req, err := r.NewRequest(opts)
 	if err != nil {
 		return resp, err
 	}
 	krakenResponse, err := req.Do()
 	if err != nil {
-		return resp, err
+		return &Response[T]{Http: krakenResponse}, err
 	}
 	resp = &Response[T]{Http: krakenResponse}
 	if err = resp.Http.JSON(&resp); err != nil {
@@ -80,9 +195,20 @@ func Call[T any](r Requestor, opts RequestOptions) (resp *Response[T], err error
 	}
 }
 
+// CallContext is like [Call] but includes a context.
+func CallContext[T any](ctx context.Context, r Requestor, opts RequestOptions) (*Response[T], error) {
+	opts.Context = ctx
+	return Call[T](r, opts)
+}
+
 // Call creates a request, checks for errors, and returns a generic response.
 func (r *REST) Call(opts RequestOptions) (*Response[any], error) {
-	return Call[any](r, opts)
+	return r.CallContext(context.Background(), opts)
+}
+
+// CallContext is like [REST.Call] but includes a context.
+func (r *REST) CallContext(ctx context.Context, opts RequestOptions) (*Response[any], error) {
+	return CallContext[any](ctx, r, opts)
 }
 
 type CreateUserRequest struct {
@@ -97,7 +223,12 @@ type CreateUserResult struct {
 //
 // https://docs.kraken.com/api/docs/embed-api/create-embed-user
 func (r *REST) CreateUser(opts *CreateUserRequest) (*Response[CreateUserResult], error) {
-	return Call[CreateUserResult](r, RequestOptions{
+	return r.CreateUserContext(context.Background(), opts)
+}
+
+// CreateUserContext is like [REST.CreateUser] but includes a context.
+func (r *REST) CreateUserContext(ctx context.Context, opts *CreateUserRequest) (*Response[CreateUserResult], error) {
+	return CallContext[CreateUserResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/CreateUser",
@@ -122,7 +253,12 @@ type UpdateUserRequest struct {
 //
 // https://docs.kraken.com/api/docs/embed-api/update-embed-user
 func (r *REST) UpdateUser(opts *UpdateUserRequest) (*Response[string], error) {
-	return Call[string](r, RequestOptions{
+	return r.UpdateUserContext(context.Background(), opts)
+}
+
+// UpdateUserContext is like [REST.UpdateUser] but includes a context.
+func (r *REST) UpdateUserContext(ctx context.Context, opts *UpdateUserRequest) (*Response[string], error) {
+	return CallContext[string](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/UpdateUser",
@@ -146,7 +282,12 @@ type GetUserResult struct {
 //
 // https://docs.kraken.com/api/docs/embed-api/get-embed-user
 func (r *REST) GetUser(opts *GetUserRequest) (*Response[GetUserResult], error) {
-	return Call[GetUserResult](r, RequestOptions{
+	return r.GetUserContext(context.Background(), opts)
+}
+
+// GetUserContext is like [REST.GetUser] but includes a context.
+func (r *REST) GetUserContext(ctx context.Context, opts *GetUserRequest) (*Response[GetUserResult], error) {
+	return CallContext[GetUserResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/GetUser",
@@ -189,7 +330,12 @@ type SubmitVerificationResult struct {
 //
 // https://docs.kraken.com/api/docs/embed-api/submit-embed-verification
 func (r *REST) VerifyUser(opts *SubmitVerificationRequest) (*Response[SubmitVerificationResult], error) {
-	return Call[SubmitVerificationResult](r, RequestOptions{
+	return r.VerifyUserContext(context.Background(), opts)
+}
+
+// VerifyUserContext is like [REST.VerifyUser] but includes a context.
+func (r *REST) VerifyUserContext(ctx context.Context, opts *SubmitVerificationRequest) (*Response[SubmitVerificationResult], error) {
+	return CallContext[SubmitVerificationResult](ctx, r, RequestOptions{
 		Auth:        true,
 		Version:     1,
 		Method:      "POST",
@@ -204,7 +350,12 @@ func (r *REST) VerifyUser(opts *SubmitVerificationRequest) (*Response[SubmitVeri
 //
 // https://docs.kraken.com/api/docs/rest-api/get-account-balance
 func (r *REST) Balances() (*Response[map[string]*decimal.Decimal], error) {
-	return Call[map[string]*decimal.Decimal](r, RequestOptions{
+	return r.BalancesContext(context.Background())
+}
+
+// BalancesContext is like [REST.Balances] but includes a context.
+func (r *REST) BalancesContext(ctx context.Context) (*Response[map[string]*decimal.Decimal], error) {
+	return CallContext[map[string]*decimal.Decimal](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Balance",
@@ -220,7 +371,12 @@ type ServerTimeResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-server-time
 func (r *REST) ServerTime() (*Response[ServerTimeResult], error) {
-	return Call[ServerTimeResult](r, RequestOptions{
+	return r.ServerTimeContext(context.Background())
+}
+
+// ServerTimeContext is like [REST.ServerTime] but includes a context.
+func (r *REST) ServerTimeContext(ctx context.Context) (*Response[ServerTimeResult], error) {
+	return CallContext[ServerTimeResult](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/0/public/Time",
 	})
@@ -245,7 +401,12 @@ type TradesHistoryResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-trade-history
 func (r *REST) TradesHistory(opts *TradesHistoryRequest) (*Response[TradesHistoryResult], error) {
-	return Call[TradesHistoryResult](r, RequestOptions{
+	return r.TradesHistoryContext(context.Background(), opts)
+}
+
+// TradesHistoryContext is like [REST.TradesHistory] but includes a context.
+func (r *REST) TradesHistoryContext(ctx context.Context, opts *TradesHistoryRequest) (*Response[TradesHistoryResult], error) {
+	return CallContext[TradesHistoryResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/TradesHistory",
@@ -267,7 +428,12 @@ type OpenOrdersResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-open-orders
 func (r *REST) OpenOrders(opts *OpenOrdersRequest) (*Response[OpenOrdersResult], error) {
-	return Call[OpenOrdersResult](r, RequestOptions{
+	return r.OpenOrdersContext(context.Background(), opts)
+}
+
+// OpenOrdersContext is like [REST.OpenOrders] but includes a context.
+func (r *REST) OpenOrdersContext(ctx context.Context, opts *OpenOrdersRequest) (*Response[OpenOrdersResult], error) {
+	return CallContext[OpenOrdersResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/OpenOrders",
@@ -295,7 +461,12 @@ type ClosedOrdersResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-closed-orders
 func (r *REST) ClosedOrders(opts *ClosedOrdersRequest) (*Response[ClosedOrdersResult], error) {
-	return Call[ClosedOrdersResult](r, RequestOptions{
+	return r.ClosedOrdersContext(context.Background(), opts)
+}
+
+// ClosedOrdersContext is like [REST.ClosedOrders] but includes a context.
+func (r *REST) ClosedOrdersContext(ctx context.Context, opts *ClosedOrdersRequest) (*Response[ClosedOrdersResult], error) {
+	return CallContext[ClosedOrdersResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/ClosedOrders",
@@ -314,7 +485,12 @@ type QueryOrdersRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-orders-info
 func (r *REST) QueryOrders(opts *QueryOrdersRequest) (*Response[map[string]ClosedOrder], error) {
-	return Call[map[string]ClosedOrder](r, RequestOptions{
+	return r.QueryOrdersContext(context.Background(), opts)
+}
+
+// QueryOrdersContext is like [REST.QueryOrders] but includes a context.
+func (r *REST) QueryOrdersContext(ctx context.Context, opts *QueryOrdersRequest) (*Response[map[string]ClosedOrder], error) {
+	return CallContext[map[string]ClosedOrder](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/QueryOrders",
@@ -355,7 +531,12 @@ type AddOrderResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/add-order
 func (r *REST) AddOrder(opts *AddOrderRequest) (*Response[AddOrderResult], error) {
-	return Call[AddOrderResult](r, RequestOptions{
+	return r.AddOrderContext(context.Background(), opts)
+}
+
+// AddOrderContext is like [REST.AddOrder] but includes a context.
+func (r *REST) AddOrderContext(ctx context.Context, opts *AddOrderRequest) (*Response[AddOrderResult], error) {
+	return CallContext[AddOrderResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/AddOrder",
@@ -378,7 +559,12 @@ type AddBatchResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/add-order-batch
 func (r *REST) AddBatch(opts *AddBatchRequest) (*Response[AddBatchResult], error) {
-	return Call[AddBatchResult](r, RequestOptions{
+	return r.AddBatchContext(context.Background(), opts)
+}
+
+// AddBatchContext is like [REST.AddBatch] but includes a context.
+func (r *REST) AddBatchContext(ctx context.Context, opts *AddBatchRequest) (*Response[AddBatchResult], error) {
+	return CallContext[AddBatchResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/AddOrderBatch",
@@ -405,7 +591,12 @@ type AmendOrderResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/amend-order
 func (r *REST) AmendOrder(opts *AmendOrderRequest) (*Response[AmendOrderResult], error) {
-	return Call[AmendOrderResult](r, RequestOptions{
+	return r.AmendOrderContext(context.Background(), opts)
+}
+
+// AmendOrderContext is like [REST.AmendOrder] but includes a context.
+func (r *REST) AmendOrderContext(ctx context.Context, opts *AmendOrderRequest) (*Response[AmendOrderResult], error) {
+	return CallContext[AmendOrderResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/AmendOrder",
@@ -427,7 +618,12 @@ type CancelResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/cancel-order
 func (r *REST) CancelOrder(opts *CancelOrderRequest) (*Response[CancelResult], error) {
-	return Call[CancelResult](r, RequestOptions{
+	return r.CancelOrderContext(context.Background(), opts)
+}
+
+// CancelOrderContext is like [REST.CancelOrder] but includes a context.
+func (r *REST) CancelOrderContext(ctx context.Context, opts *CancelOrderRequest) (*Response[CancelResult], error) {
+	return CallContext[CancelResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/CancelOrder",
@@ -439,7 +635,12 @@ func (r *REST) CancelOrder(opts *CancelOrderRequest) (*Response[CancelResult], e
 //
 // https://docs.kraken.com/api/docs/rest-api/cancel-all-orders
 func (r *REST) CancelAll() (*Response[CancelResult], error) {
-	return Call[CancelResult](r, RequestOptions{
+	return r.CancelAllContext(context.Background())
+}
+
+// CancelAllContext is like [REST.CancelAll] but includes a context.
+func (r *REST) CancelAllContext(ctx context.Context) (*Response[CancelResult], error) {
+	return CallContext[CancelResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/CancelAll",
@@ -456,7 +657,12 @@ type AssetsRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-asset-info
 func (r *REST) Assets(opts *AssetsRequest) (*Response[map[string]AssetInfo], error) {
-	return Call[map[string]AssetInfo](r, RequestOptions{
+	return r.AssetsContext(context.Background(), opts)
+}
+
+// AssetsContext is like [REST.Assets] but includes a context.
+func (r *REST) AssetsContext(ctx context.Context, opts *AssetsRequest) (*Response[map[string]AssetInfo], error) {
+	return CallContext[map[string]AssetInfo](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/0/public/Assets",
 		Query:  opts,
@@ -474,7 +680,12 @@ type AssetPairsRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-tradable-asset-pairs
 func (r *REST) AssetPairs(opts *AssetPairsRequest) (*Response[map[string]AssetPair], error) {
-	return Call[map[string]AssetPair](r, RequestOptions{
+	return r.AssetPairsContext(context.Background(), opts)
+}
+
+// AssetPairsContext is like [REST.AssetPairs] but includes a context.
+func (r *REST) AssetPairsContext(ctx context.Context, opts *AssetPairsRequest) (*Response[map[string]AssetPair], error) {
+	return CallContext[map[string]AssetPair](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/0/public/AssetPairs",
 		Query:  opts,
@@ -489,7 +700,12 @@ type TickerRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-ticker-information
 func (r *REST) Ticker(opts *TickerRequest) (*Response[map[string]AssetTickerInfo], error) {
-	return Call[map[string]AssetTickerInfo](r, RequestOptions{
+	return r.TickerContext(context.Background(), opts)
+}
+
+// TickerContext is like [REST.Ticker] but includes a context.
+func (r *REST) TickerContext(ctx context.Context, opts *TickerRequest) (*Response[map[string]AssetTickerInfo], error) {
+	return CallContext[map[string]AssetTickerInfo](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/0/public/Ticker",
 		Query:  opts,
@@ -505,7 +721,12 @@ type OrderBookRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-order-book
 func (r *REST) OrderBook(opts *OrderBookRequest) (*Response[map[string]OrderBook], error) {
-	return Call[map[string]OrderBook](r, RequestOptions{
+	return r.OrderBookContext(context.Background(), opts)
+}
+
+// OrderBookContext is like [REST.OrderBook] but includes a context.
+func (r *REST) OrderBookContext(ctx context.Context, opts *OrderBookRequest) (*Response[map[string]OrderBook], error) {
+	return CallContext[map[string]OrderBook](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/0/public/Depth",
 		Query:  opts,
@@ -522,7 +743,12 @@ type RecentTradesRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-recent-trades
 func (r *REST) RecentTrades(opts *RecentTradesRequest) (*Response[map[string]any], error) {
-	return Call[map[string]any](r, RequestOptions{
+	return r.RecentTradesContext(context.Background(), opts)
+}
+
+// RecentTradesContext is like [REST.RecentTrades] but includes a context.
+func (r *REST) RecentTradesContext(ctx context.Context, opts *RecentTradesRequest) (*Response[map[string]any], error) {
+	return CallContext[map[string]any](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/0/public/Trades",
 		Query:  opts,
@@ -539,7 +765,12 @@ type OHLCRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-ohlc-data
 func (r *REST) OHLC(opts *OHLCRequest) (*Response[map[string]any], error) {
-	return Call[map[string]any](r, RequestOptions{
+	return r.OHLCContext(context.Background(), opts)
+}
+
+// OHLCContext is like [REST.OHLC] but includes a context.
+func (r *REST) OHLCContext(ctx context.Context, opts *OHLCRequest) (*Response[map[string]any], error) {
+	return CallContext[map[string]any](ctx, r, RequestOptions{
 		Method: "GET",
 		Path:   "/0/public/OHLC",
 		Query:  opts,
@@ -555,7 +786,12 @@ type GetWebSocketsTokenResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-websockets-token
 func (r *REST) GetWebSocketsToken() (*Response[GetWebSocketsTokenResult], error) {
-	return Call[GetWebSocketsTokenResult](r, RequestOptions{
+	return r.GetWebSocketsTokenContext(context.Background())
+}
+
+// GetWebSocketsTokenContext is like [REST.GetWebSocketsToken] but includes a context.
+func (r *REST) GetWebSocketsTokenContext(ctx context.Context) (*Response[GetWebSocketsTokenResult], error) {
+	return CallContext[GetWebSocketsTokenResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/GetWebSocketsToken",
@@ -571,7 +807,12 @@ type DepositMethodsRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-deposit-methods
 func (r *REST) DepositMethods(opts *DepositMethodsRequest) (*Response[[]DepositMethod], error) {
-	return Call[[]DepositMethod](r, RequestOptions{
+	return r.DepositMethodsContext(context.Background(), opts)
+}
+
+// DepositMethodsContext is like [REST.DepositMethods] but includes a context.
+func (r *REST) DepositMethodsContext(ctx context.Context, opts *DepositMethodsRequest) (*Response[[]DepositMethod], error) {
+	return CallContext[[]DepositMethod](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/DepositMethods",
@@ -590,7 +831,12 @@ type DepositAddressesRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-deposit-methods
 func (r *REST) DepositAddresses(opts *DepositAddressesRequest) (*Response[[]DepositAddress], error) {
-	return Call[[]DepositAddress](r, RequestOptions{
+	return r.DepositAddressesContext(context.Background(), opts)
+}
+
+// DepositAddressesContext is like [REST.DepositAddresses] but includes a context.
+func (r *REST) DepositAddressesContext(ctx context.Context, opts *DepositAddressesRequest) (*Response[[]DepositAddress], error) {
+	return CallContext[[]DepositAddress](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/DepositMethods",
@@ -612,7 +858,12 @@ type DepositStatusRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-status-recent-deposits
 func (r *REST) DepositStatus(opts *DepositStatusRequest) (*Response[[]DepositStatus], error) {
-	return Call[[]DepositStatus](r, RequestOptions{
+	return r.DepositStatusContext(context.Background(), opts)
+}
+
+// DepositStatusContext is like [REST.DepositStatus] but includes a context.
+func (r *REST) DepositStatusContext(ctx context.Context, opts *DepositStatusRequest) (*Response[[]DepositStatus], error) {
+	return CallContext[[]DepositStatus](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/DepositStatus",
@@ -630,7 +881,12 @@ type WithdrawMethodsRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-withdraw-methods
 func (r *REST) WithdrawMethods(opts *WithdrawMethodsRequest) (*Response[[]WithdrawMethod], error) {
-	return Call[[]WithdrawMethod](r, RequestOptions{
+	return r.WithdrawMethodsContext(context.Background(), opts)
+}
+
+// WithdrawMethodsContext is like [REST.WithdrawMethods] but includes a context.
+func (r *REST) WithdrawMethodsContext(ctx context.Context, opts *WithdrawMethodsRequest) (*Response[[]WithdrawMethod], error) {
+	return CallContext[[]WithdrawMethod](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/WithdrawMethods",
@@ -650,7 +906,12 @@ type WithdrawAddressesRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-withdrawal-addresses
 func (r *REST) WithdrawAddresses(opts *WithdrawAddressesRequest) (*Response[[]WithdrawAddress], error) {
-	return Call[[]WithdrawAddress](r, RequestOptions{
+	return r.WithdrawAddressesContext(context.Background(), opts)
+}
+
+// WithdrawAddressesContext is like [REST.WithdrawAddresses] but includes a context.
+func (r *REST) WithdrawAddressesContext(ctx context.Context, opts *WithdrawAddressesRequest) (*Response[[]WithdrawAddress], error) {
+	return CallContext[[]WithdrawAddress](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/WithdrawAddresses",
@@ -671,7 +932,12 @@ type WithdrawInfoRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-withdrawal-information
 func (r *REST) WithdrawInfo(opts *WithdrawInfoRequest) (*Response[WithdrawInfo], error) {
-	return Call[WithdrawInfo](r, RequestOptions{
+	return r.WithdrawInfoContext(context.Background(), opts)
+}
+
+// WithdrawInfoContext is like [REST.WithdrawInfo] but includes a context.
+func (r *REST) WithdrawInfoContext(ctx context.Context, opts *WithdrawInfoRequest) (*Response[WithdrawInfo], error) {
+	return CallContext[WithdrawInfo](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/WithdrawInfo",
@@ -696,7 +962,12 @@ type WithdrawResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/withdraw-funds
 func (r *REST) Withdraw(opts *WithdrawRequest) (*Response[WithdrawResult], error) {
-	return Call[WithdrawResult](r, RequestOptions{
+	return r.WithdrawContext(context.Background(), opts)
+}
+
+// WithdrawContext is like [REST.Withdraw] but includes a context.
+func (r *REST) WithdrawContext(ctx context.Context, opts *WithdrawRequest) (*Response[WithdrawResult], error) {
+	return CallContext[WithdrawResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Withdraw",
@@ -718,7 +989,12 @@ type WithdrawStatusRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-status-recent-withdrawals
 func (r *REST) WithdrawStatus(opts *WithdrawStatusRequest) (*Response[[]WithdrawStatus], error) {
-	return Call[[]WithdrawStatus](r, RequestOptions{
+	return r.WithdrawStatusContext(context.Background(), opts)
+}
+
+// WithdrawStatusContext is like [REST.WithdrawStatus] but includes a context.
+func (r *REST) WithdrawStatusContext(ctx context.Context, opts *WithdrawStatusRequest) (*Response[[]WithdrawStatus], error) {
+	return CallContext[[]WithdrawStatus](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/WithdrawStatus",
@@ -736,7 +1012,12 @@ type WithdrawCancelRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/cancel-withdrawal
 func (r *REST) WithdrawCancel(opts *WithdrawCancelRequest) (*Response[bool], error) {
-	return Call[bool](r, RequestOptions{
+	return r.WithdrawCancelContext(context.Background(), opts)
+}
+
+// WithdrawCancelContext is like [REST.WithdrawCancel] but includes a context.
+func (r *REST) WithdrawCancelContext(ctx context.Context, opts *WithdrawCancelRequest) (*Response[bool], error) {
+	return CallContext[bool](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/WithdrawCancel",
@@ -760,7 +1041,12 @@ type WalletTransferResponse struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/wallet-transfer
 func (r *REST) WalletTransfer(opts *WalletTransferRequest) (*Response[WalletTransferResponse], error) {
-	return Call[WalletTransferResponse](r, RequestOptions{
+	return r.WalletTransferContext(context.Background(), opts)
+}
+
+// WalletTransferContext is like [REST.WalletTransfer] but includes a context.
+func (r *REST) WalletTransferContext(ctx context.Context, opts *WalletTransferRequest) (*Response[WalletTransferResponse], error) {
+	return CallContext[WalletTransferResponse](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/WalletTransfer",
@@ -777,7 +1063,12 @@ type CreateSubaccountRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/create-subaccount
 func (r *REST) CreateSubaccount(opts *CreateSubaccountRequest) (*Response[bool], error) {
-	return Call[bool](r, RequestOptions{
+	return r.CreateSubaccountContext(context.Background(), opts)
+}
+
+// CreateSubaccountContext is like [REST.CreateSubaccount] but includes a context.
+func (r *REST) CreateSubaccountContext(ctx context.Context, opts *CreateSubaccountRequest) (*Response[bool], error) {
+	return CallContext[bool](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/CreateSubaccount",
@@ -801,7 +1092,12 @@ type AccountTransferResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/account-transfer
 func (r *REST) AccountTransfer(opts *AccountTransferRequest) (*Response[AccountTransferResult], error) {
-	return Call[AccountTransferResult](r, RequestOptions{
+	return r.AccountTransferContext(context.Background(), opts)
+}
+
+// AccountTransferContext is like [REST.AccountTransfer] but includes a context.
+func (r *REST) AccountTransferContext(ctx context.Context, opts *AccountTransferRequest) (*Response[AccountTransferResult], error) {
+	return CallContext[AccountTransferResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/AccountTransfer",
@@ -818,7 +1114,12 @@ type EarnAllocateRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/allocate-strategy
 func (r *REST) EarnAllocate(opts *EarnAllocateRequest) (*Response[bool], error) {
-	return Call[bool](r, RequestOptions{
+	return r.EarnAllocateContext(context.Background(), opts)
+}
+
+// EarnAllocateContext is like [REST.EarnAllocate] but includes a context.
+func (r *REST) EarnAllocateContext(ctx context.Context, opts *EarnAllocateRequest) (*Response[bool], error) {
+	return CallContext[bool](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Earn/Allocate",
@@ -835,7 +1136,12 @@ type EarnDeallocateRequest struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/deallocate-strategy
 func (r *REST) EarnDeallocate(opts *EarnDeallocateRequest) (*Response[bool], error) {
-	return Call[bool](r, RequestOptions{
+	return r.EarnDeallocateContext(context.Background(), opts)
+}
+
+// EarnDeallocateContext is like [REST.EarnDeallocate] but includes a context.
+func (r *REST) EarnDeallocateContext(ctx context.Context, opts *EarnDeallocateRequest) (*Response[bool], error) {
+	return CallContext[bool](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Earn/Deallocate",
@@ -855,7 +1161,12 @@ type EarnStatusResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/get-allocate-strategy-status
 func (r *REST) EarnAllocateStatus(opts *EarnStatusRequest) (*Response[EarnStatusResult], error) {
-	return Call[EarnStatusResult](r, RequestOptions{
+	return r.EarnAllocateStatusContext(context.Background(), opts)
+}
+
+// EarnAllocateStatusContext is like [REST.EarnAllocateStatus] but includes a context.
+func (r *REST) EarnAllocateStatusContext(ctx context.Context, opts *EarnStatusRequest) (*Response[EarnStatusResult], error) {
+	return CallContext[EarnStatusResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Earn/AllocateStatus",
@@ -867,7 +1178,12 @@ func (r *REST) EarnAllocateStatus(opts *EarnStatusRequest) (*Response[EarnStatus
 //
 // https://docs.kraken.com/api/docs/rest-api/get-deallocate-strategy-status
 func (r *REST) EarnDeallocateStatus(opts *EarnStatusRequest) (*Response[EarnStatusResult], error) {
-	return Call[EarnStatusResult](r, RequestOptions{
+	return r.EarnDeallocateStatusContext(context.Background(), opts)
+}
+
+// EarnDeallocateStatusContext is like [REST.EarnDeallocateStatus] but includes a context.
+func (r *REST) EarnDeallocateStatusContext(ctx context.Context, opts *EarnStatusRequest) (*Response[EarnStatusResult], error) {
+	return CallContext[EarnStatusResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Earn/DeallocateStatus",
@@ -891,7 +1207,12 @@ type EarnStrategiesResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/list-strategies
 func (r *REST) EarnStrategies(opts *EarnStrategiesRequest) (*Response[EarnStrategiesResult], error) {
-	return Call[EarnStrategiesResult](r, RequestOptions{
+	return r.EarnStrategiesContext(context.Background(), opts)
+}
+
+// EarnStrategiesContext is like [REST.EarnStrategies] but includes a context.
+func (r *REST) EarnStrategiesContext(ctx context.Context, opts *EarnStrategiesRequest) (*Response[EarnStrategiesResult], error) {
+	return CallContext[EarnStrategiesResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Earn/Strategies",
@@ -917,7 +1238,12 @@ type EarnAllocationsResult struct {
 //
 // https://docs.kraken.com/api/docs/rest-api/list-allocations
 func (r *REST) EarnAllocations(opts *EarnAllocationsRequest) (*Response[EarnAllocationsResult], error) {
-	return Call[EarnAllocationsResult](r, RequestOptions{
+	return r.EarnAllocationsContext(context.Background(), opts)
+}
+
+// EarnAllocationsContext is like [REST.EarnAllocations] but includes a context.
+func (r *REST) EarnAllocationsContext(ctx context.Context, opts *EarnAllocationsRequest) (*Response[EarnAllocationsResult], error) {
+	return CallContext[EarnAllocationsResult](ctx, r, RequestOptions{
 		Auth:   true,
 		Method: "POST",
 		Path:   "/0/private/Earn/Allocations",
@@ -927,7 +1253,12 @@ func (r *REST) EarnAllocations(opts *EarnAllocationsRequest) (*Response[EarnAllo
 
 // TickerSingle calls [REST.Ticker] and returns the first [AssetTickerInfo] item from the result.
 func (r *REST) TickerSingle(pair string) (*AssetTickerInfo, error) {
-	ticker, err := r.Ticker(&TickerRequest{
+	return r.TickerSingleContext(context.Background(), pair)
+}
+
+// TickerSingleContext is like [REST.TickerSingle] but includes a context.
+func (r *REST) TickerSingleContext(ctx context.Context, pair string) (*AssetTickerInfo, error) {
+	ticker, err := r.TickerContext(ctx, &TickerRequest{
 		Pair: pair,
 	})
 	if err != nil {
@@ -941,6 +1272,7 @@ func (r *REST) TickerSingle(pair string) (*AssetTickerInfo, error) {
 
 // RequestOptions contain the parameters for [NewRequest].
 type RequestOptions struct {
+	Context     context.Context
 	Auth        bool
 	Version     int
 	PublicKey   string
@@ -1001,6 +1333,7 @@ func NewRequest(opts RequestOptions) (req *kraken.Request, err error) {
 		contentType = "application/json"
 	}
 	req, err = kraken.NewRequestWithOptions(kraken.RequestOptions{
+		Context:     opts.Context,
 		Method:      opts.Method,
 		URL:         opts.BaseURL,
 		Headers:     opts.Headers,
@@ -1052,14 +1385,14 @@ type Response[T any] struct {
 	Http   *kraken.Response `json:"-"`
 }
 
-// GetError returns the API error message if it exists on the body.
+// GetError returns the API errors as [kraken.Error] if they exist on the body.
 func (r *Response[T]) GetError() error {
 	if len(r.Error) == 0 {
 		return nil
 	}
 	var err error
 	for _, errorEntry := range r.Error {
-		err = errors.Join(err, fmt.Errorf("%v", errorEntry))
+		err = errors.Join(err, kraken.ParseSpotError(fmt.Sprint(errorEntry)))
 	}
 	return err
 }
@@ -0,0 +1,87 @@
+package spot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// TokenManager caches a WebSocket authentication token and retrieves a new one once it is about to expire.
+// A single manager can be shared by several [WebSocket] clients using the same API key.
+//
+// https://docs.kraken.com/api/docs/guides/spot-ws-auth
+type TokenManager struct {
+	REST *REST
+
+	// Time before the expiry of an unused token at which a new one is retrieved.
+	RefreshMargin time.Duration
+
+	// Called with the new token after it is retrieved.
+	OnRefreshed *callback.Manager[string]
+
+	token   string
+	expires time.Time
+	mux     sync.Mutex
+}
+
+// NewTokenManager constructs a [TokenManager] that retrieves tokens with the given [REST] client.
+func NewTokenManager(rest *REST) *TokenManager {
+	return &TokenManager{
+		REST:          rest,
+		RefreshMargin: 30 * time.Second,
+		OnRefreshed:   callback.NewManager[string](),
+	}
+}
+
+// Token returns the cached token or retrieves a new one if there is none or it is stale.
+func (m *TokenManager) Token() (string, error) {
+	return m.TokenContext(context.Background())
+}
+
+// TokenContext is like [TokenManager.Token] but includes a context.
+func (m *TokenManager) TokenContext(ctx context.Context) (string, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.token != "" && time.Now().Add(m.RefreshMargin).Before(m.expires) {
+		return m.token, nil
+	}
+	resp, err := m.REST.GetWebSocketsTokenContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	expires := time.Duration(resp.Result.Expires) * time.Second
+	if expires <= 0 {
+		expires = 15 * time.Minute
+	}
+	m.token = resp.Result.Token
+	m.expires = time.Now().Add(expires)
+	m.OnRefreshed.Call(m.token)
+	return m.token, nil
+}
+
+// Invalidate discards the cached token if it matches, so the next call of [TokenManager.Token] retrieves a new one.
+// Clients sharing the manager that report the same rejected token only cause a single refresh.
+func (m *TokenManager) Invalidate(token string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.token == token {
+		m.token = ""
+		m.expires = time.Time{}
+	}
+}
+
+// Expires returns the time at which the cached token expires if it is not used.
+func (m *TokenManager) Expires() time.Time {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.expires
+}
+
+// isInvalidToken returns whether an error indicates that the server rejected the token.
+func isInvalidToken(err error) bool {
+	return errors.Is(err, kraken.ErrInvalidToken) || errors.Is(err, kraken.ErrInvalidSession)
+}
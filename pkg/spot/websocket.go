@@ -1,12 +1,21 @@
 package spot
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/krakenfx/api-go/v2/internal/helper"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
 )
 
 // WebSocket wraps a [WebSocketBase] struct with order management and subscription request functions.
 type WebSocket struct {
 	*WebSocketBase
+	*Dispatcher
 }
 
 // NewWebSocket constructs a new [WebSocket] struct with default values.
@@ -15,7 +24,13 @@ type WebSocket struct {
 func NewWebSocket() *WebSocket {
 	s := &WebSocket{
 		WebSocketBase: NewWebSocketBase(),
+		Dispatcher:    NewDispatcher(),
 	}
+	s.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
+		if err := s.Dispatch(e.Data); err != nil {
+			s.OnDecodeError.Call(err)
+		}
+	})
 	return s
 }
 
@@ -89,11 +104,73 @@ func (s *WebSocket) SubInstruments(options ...map[string]any) error {
 	return s.SubPublic("instrument", options...)
 }
 
+// UnsubExecutions sends an unsubscribe request for the order and trade events of the authenticated user.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/executions
+func (s *WebSocket) UnsubExecutions(options ...map[string]any) error {
+	return s.UnsubPrivate("executions", options...)
+}
+
+// UnsubBalances sends an unsubscribe request for the asset balances and ledger entries of the authenticated user.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/balances
+func (s *WebSocket) UnsubBalances(options ...map[string]any) error {
+	return s.UnsubPrivate("balances", options...)
+}
+
+// UnsubTicker sends an unsubscribe request for the level 1 market data of the specified symbols.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/ticker
+func (s *WebSocket) UnsubTicker(symbols []string, options ...map[string]any) error {
+	return s.UnsubPublic("ticker", append([]map[string]any{{"params": map[string]any{"symbol": symbols}}}, options...)...)
+}
+
+// UnsubBook sends an unsubscribe request for the level 2 market data of the specified symbols.
+// The depth must match the one of the subscription.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/book
+func (s *WebSocket) UnsubBook(symbols []string, depth int, options ...map[string]any) error {
+	return s.UnsubPublic("book", append([]map[string]any{{
+		"params": map[string]any{
+			"symbol": symbols,
+			"depth":  depth,
+		},
+	}}, options...)...)
+}
+
+// UnsubL3 sends an unsubscribe request for the level 3 market data of the specified symbols.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/level3
+func (s *WebSocket) UnsubL3(symbols []string, options ...map[string]any) error {
+	return s.UnsubPrivate("level3", append([]map[string]any{{"params": map[string]any{"symbol": symbols}}}, options...)...)
+}
+
+// UnsubCandles sends an unsubscribe request for the candles of the specified symbols.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/ohlc
+func (s *WebSocket) UnsubCandles(symbols []string, options ...map[string]any) error {
+	return s.UnsubPublic("ohlc", append([]map[string]any{{"params": map[string]any{"symbol": symbols}}}, options...)...)
+}
+
+// UnsubTrades sends an unsubscribe request for the trade events of the specified symbols.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/trade
+func (s *WebSocket) UnsubTrades(symbols []string, options ...map[string]any) error {
+	return s.UnsubPublic("trade", append([]map[string]any{{"params": map[string]any{"symbol": symbols}}}, options...)...)
+}
+
+// UnsubInstruments sends an unsubscribe request for asset and asset pair information.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/instrument
+func (s *WebSocket) UnsubInstruments(options ...map[string]any) error {
+	return s.UnsubPublic("instrument", options...)
+}
+
 // AddOrder places a new order.
 //
 // https://docs.kraken.com/api/docs/websocket-v2/add_order
-func (s *WebSocket) AddOrder(orderType string, side string, quantity float64, symbol string, options ...map[string]any) error {
-	return s.SendPrivate(helper.Maps(map[string]any{
+func (s *WebSocket) AddOrder(orderType string, side string, quantity float64, symbol string, options ...map[string]any) (*PendingRequest[WebSocketAddOrderResult], error) {
+	return SendRequest[WebSocketAddOrderResult](s.WebSocketBase, true, helper.Maps(map[string]any{
 		"method": "add_order",
 		"params": map[string]any{
 			"order_type": orderType,
@@ -108,8 +185,8 @@ func (s *WebSocket) AddOrder(orderType string, side string, quantity float64, sy
 // This enables order ID and queue priority to be maintained where possible.
 //
 // https://docs.kraken.com/api/docs/websocket-v2/amend_order
-func (s *WebSocket) AmendOrder(options ...map[string]any) error {
-	return s.SendPrivate(helper.Maps(map[string]any{
+func (s *WebSocket) AmendOrder(options ...map[string]any) (*PendingRequest[WebSocketAmendOrderResult], error) {
+	return SendRequest[WebSocketAmendOrderResult](s.WebSocketBase, true, helper.Maps(map[string]any{
 		"method": "amend_order",
 	}, options...))
 }
@@ -117,17 +194,142 @@ func (s *WebSocket) AmendOrder(options ...map[string]any) error {
 // CancelAllOrders cancels all open orders.
 //
 // https://docs.kraken.com/api/docs/websocket-v2/cancel_all
-func (s *WebSocket) CancelAllOrders(options ...map[string]any) error {
-	return s.SendPrivate(helper.Maps(map[string]any{
+func (s *WebSocket) CancelAllOrders(options ...map[string]any) (*PendingRequest[WebSocketCancelAllResult], error) {
+	return SendRequest[WebSocketCancelAllResult](s.WebSocketBase, true, helper.Maps(map[string]any{
 		"method": "cancel_all",
 	}, options...))
 }
 
 // CancelOrder cancels an individual or set of open orders provided by `order_id`, `cl_ord_id`, or `order_userref`
 //
+// The request resolves with the first acknowledgement. When cancelling multiple orders, the acknowledgements of the other orders are only delivered to OnReceived.
+//
 // https://docs.kraken.com/api/docs/websocket-v2/cancel_order
-func (s *WebSocket) CancelOrder(options ...map[string]any) error {
-	return s.SendPrivate(helper.Maps(map[string]any{
+func (s *WebSocket) CancelOrder(options ...map[string]any) (*PendingRequest[WebSocketCancelOrderResult], error) {
+	return SendRequest[WebSocketCancelOrderResult](s.WebSocketBase, true, helper.Maps(map[string]any{
 		"method": "cancel_order",
 	}, options...))
 }
+
+type WebSocketBatchAddRequest struct {
+	Symbol   string                   `json:"symbol,omitempty"`
+	Orders   []*WebSocketOrderRequest `json:"orders,omitempty"`
+	Deadline string                   `json:"deadline,omitempty"`
+	Validate bool                     `json:"validate,omitempty"`
+}
+
+// tradingDecimals lists the request fields sent as JSON numbers instead of the quoted [decimal.Decimal] form.
+var tradingDecimals = map[string]bool{
+	"order_qty":      true,
+	"limit_price":    true,
+	"trigger_price":  true,
+	"price":          true,
+	"display_qty":    true,
+	"cash_order_qty": true,
+}
+
+// tradingParams converts a trading request into params with exact prices and quantities as JSON numbers.
+func tradingParams(body any) (map[string]any, error) {
+	params, err := bodyMap(body)
+	if err != nil {
+		return nil, err
+	}
+	numbers(params)
+	return params, nil
+}
+
+// numbers replaces the decimal strings in v with [json.Number] values.
+func numbers(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if s, ok := value.(string); ok && tradingDecimals[key] {
+				v[key] = json.Number(s)
+				continue
+			}
+			numbers(value)
+		}
+	case []any:
+		for _, value := range v {
+			numbers(value)
+		}
+	}
+}
+
+// BatchAdd places a collection of 2 to 15 orders on a single pair.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/batch_add
+func (s *WebSocket) BatchAdd(opts *WebSocketBatchAddRequest, options ...map[string]any) (*PendingRequest[[]WebSocketAddOrderResult], error) {
+	params, err := tradingParams(opts)
+	if err != nil {
+		return nil, fmt.Errorf("batch_add params: %w", err)
+	}
+	return SendRequest[[]WebSocketAddOrderResult](s.WebSocketBase, true, helper.Maps(map[string]any{
+		"method": "batch_add",
+		"params": params,
+	}, options...))
+}
+
+type WebSocketBatchCancelRequest struct {
+	Orders  []string `json:"orders,omitempty"`
+	ClOrdID []string `json:"cl_ord_id,omitempty"`
+}
+
+// BatchCancel cancels a collection of 2 to 50 orders provided by `order_id`, `order_userref`, or `cl_ord_id`.
+// The number of cancelled orders is stored on [WebSocketResponse.OrdersCancelled].
+//
+// https://docs.kraken.com/api/docs/websocket-v2/batch_cancel
+func (s *WebSocket) BatchCancel(opts *WebSocketBatchCancelRequest, options ...map[string]any) (*PendingRequest[any], error) {
+	params, err := bodyMap(opts)
+	if err != nil {
+		return nil, fmt.Errorf("batch_cancel params: %w", err)
+	}
+	return SendRequest[any](s.WebSocketBase, true, helper.Maps(map[string]any{
+		"method": "batch_cancel",
+		"params": params,
+	}, options...))
+}
+
+type WebSocketEditOrderRequest struct {
+	OrderID       string             `json:"order_id,omitempty"`
+	Symbol        string             `json:"symbol,omitempty"`
+	OrderQty      *decimal.Decimal   `json:"order_qty,omitempty"`
+	LimitPrice    *decimal.Decimal   `json:"limit_price,omitempty"`
+	DisplayQty    *decimal.Decimal   `json:"display_qty,omitempty"`
+	Triggers      *WebSocketTriggers `json:"triggers,omitempty"`
+	PostOnly      bool               `json:"post_only,omitempty"`
+	ReduceOnly    bool               `json:"reduce_only,omitempty"`
+	OrderUserref  int                `json:"order_userref,omitempty"`
+	Deadline      string             `json:"deadline,omitempty"`
+	FeePreference string             `json:"fee_preference,omitempty"`
+	NoMPP         bool               `json:"no_mpp,omitempty"`
+	Validate      bool               `json:"validate,omitempty"`
+}
+
+// EditOrder cancels an open order and replaces it with a new order with a new order ID.
+// Use [WebSocket.AmendOrder] to keep the queue priority.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/edit_order
+func (s *WebSocket) EditOrder(opts *WebSocketEditOrderRequest, options ...map[string]any) (*PendingRequest[WebSocketEditOrderResult], error) {
+	params, err := tradingParams(opts)
+	if err != nil {
+		return nil, fmt.Errorf("edit_order params: %w", err)
+	}
+	return SendRequest[WebSocketEditOrderResult](s.WebSocketBase, true, helper.Maps(map[string]any{
+		"method": "edit_order",
+		"params": params,
+	}, options...))
+}
+
+// CancelAllOrdersAfter cancels all open orders once the timeout expires unless the timer is reset by another call.
+// A timeout of 0 disables the timer and other timeouts are rounded up to whole seconds.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/cancel_after
+func (s *WebSocket) CancelAllOrdersAfter(timeout time.Duration, options ...map[string]any) (*PendingRequest[WebSocketCancelAfterResult], error) {
+	return SendRequest[WebSocketCancelAfterResult](s.WebSocketBase, true, helper.Maps(map[string]any{
+		"method": "cancel_all_orders_after",
+		"params": map[string]any{
+			"timeout": int(math.Ceil(timeout.Seconds())),
+		},
+	}, options...))
+}
@@ -0,0 +1,419 @@
+package spot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/krakenfx/api-go/v2/pkg/callback"
+	"github.com/krakenfx/api-go/v2/pkg/decimal"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// newTestServer starts a WebSocket server that replies to each request with the result of handler.
+func newTestServer(t *testing.T, handler func(request map[string]any) []any) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %s", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		for {
+			var request map[string]any
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			for _, response := range handler(request) {
+				if err := conn.WriteJSON(response); err != nil {
+					return
+				}
+			}
+		}
+	}))
+}
+
+func TestWebSocketRequest(t *testing.T) {
+	server := newTestServer(t, func(request map[string]any) []any {
+		response := map[string]any{
+			"method":  request["method"],
+			"req_id":  request["req_id"],
+			"success": true,
+		}
+		params, _ := request["params"].(map[string]any)
+		if params["symbol"] == "BTC/EUR" {
+			response["success"] = false
+			response["error"] = "EOrder:Insufficient funds"
+		} else {
+			response["result"] = map[string]any{"order_id": "OABC-123"}
+		}
+		return []any{map[string]any{"channel": "heartbeat"}, response}
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	first, err := ws.AddOrder("limit", "buy", 1, "BTC/USD")
+	if err != nil {
+		t.Fatalf("AddOrder: %s", err)
+	}
+	second, err := ws.AddOrder("limit", "buy", 1, "BTC/EUR")
+	if err != nil {
+		t.Fatalf("AddOrder: %s", err)
+	}
+	if first.ReqID == second.ReqID {
+		t.Errorf("expected distinct req_id, got %d twice", first.ReqID)
+	}
+	response, err := first.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %s", err)
+	}
+	if response.ReqID != first.ReqID || response.Result.OrderID != "OABC-123" {
+		t.Errorf("expected req_id %d with order OABC-123, got %+v", first.ReqID, response)
+	}
+	if _, err := second.Wait(); !errors.Is(err, kraken.ErrInsufficientFunds) {
+		t.Errorf("expected ErrInsufficientFunds, got %v", err)
+	}
+}
+
+func TestWebSocketBatchAdd(t *testing.T) {
+	prices := make(chan any, 2)
+	server := newTestServer(t, func(request map[string]any) []any {
+		params, _ := request["params"].(map[string]any)
+		orders, _ := params["orders"].([]any)
+		var result []any
+		for _, order := range orders {
+			order, _ := order.(map[string]any)
+			prices <- order["limit_price"]
+			result = append(result, map[string]any{"order_id": order["cl_ord_id"]})
+		}
+		return []any{map[string]any{
+			"method":  request["method"],
+			"req_id":  request["req_id"],
+			"result":  result,
+			"success": params["symbol"] == "BTC/USD",
+		}}
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	pending, err := ws.BatchAdd(&WebSocketBatchAddRequest{
+		Symbol: "BTC/USD",
+		Orders: []*WebSocketOrderRequest{
+			{OrderType: "limit", Side: "buy", OrderQty: decimal.NewFromInt64(1), LimitPrice: decimal.NewFromInt64(100), ClOrdID: "a"},
+			{OrderType: "limit", Side: "sell", OrderQty: decimal.NewFromInt64(1), LimitPrice: decimal.NewFromFloat64(200.5), ClOrdID: "b"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BatchAdd: %s", err)
+	}
+	response, err := pending.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %s", err)
+	}
+	if len(response.Result) != 2 || response.Result[0].OrderID != "a" || response.Result[1].OrderID != "b" {
+		t.Errorf("expected orders a and b, got %+v", response.Result)
+	}
+	if first, second := <-prices, <-prices; first != float64(100) || second != 200.5 {
+		t.Errorf("expected numeric limit prices 100 and 200.5, got %#v and %#v", first, second)
+	}
+}
+
+func TestWebSocketRestore(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	received := make(chan map[string]any, 10)
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %s", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		first := connections.Add(1) == 1
+		for {
+			var request map[string]any
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			received <- request
+			if first {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	ws.ReconnectWait = 10 * time.Millisecond
+	restored := make(chan *kraken.RestoreEvent, 1)
+	ws.OnRestored.Recurring(func(e *callback.Event[*kraken.RestoreEvent]) {
+		restored <- e.Data
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	if err := ws.SubTicker([]string{"BTC/USD"}); err != nil {
+		t.Fatalf("SubTicker: %s", err)
+	}
+	select {
+	case event := <-restored:
+		if event.Err != nil || len(event.Subscriptions) != 1 || event.Subscriptions[0].Channel != "ticker" {
+			t.Errorf("expected ticker to be restored, got %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for restore")
+	}
+	for range 2 {
+		request := <-received
+		if channel := request["params"].(map[string]any)["channel"]; channel != "ticker" {
+			t.Errorf("expected ticker subscription, got %v", request)
+		}
+	}
+}
+
+func TestWebSocketReconnectState(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %s", err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		first := connections.Add(1) == 1
+		for {
+			var request map[string]any
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			response := map[string]any{"method": request["method"], "req_id": request["req_id"], "success": true}
+			if params, ok := request["params"].(map[string]any); ok {
+				response["result"] = map[string]any{"channel": params["channel"], "symbol": "BTC/USD"}
+			}
+			if err := conn.WriteJSON(response); err != nil || first {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	ws.ReconnectWait = 10 * time.Millisecond
+	pings := make(chan *PendingRequest[any], 1)
+	ws.OnReconnected.Recurring(func(e *callback.Event[any]) {
+		pending, err := SendRequest[any](ws.WebSocketBase, false, map[string]any{"method": "ping"})
+		if err != nil {
+			t.Errorf("SendRequest: %s", err)
+		}
+		pings <- pending
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	if err := ws.SubTicker([]string{"BTC/USD"}); err != nil {
+		t.Fatalf("SubTicker: %s", err)
+	}
+	select {
+	case pending := <-pings:
+		if _, err := pending.Wait(); err != nil {
+			t.Errorf("expected request sent after reconnecting to succeed, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for reconnect")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !ws.IsSubscribed("ticker", "BTC/USD") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !ws.IsSubscribed("ticker", "BTC/USD") {
+		t.Errorf("expected restored ticker subscription to be kept")
+	}
+}
+
+func TestWebSocketSubscriptions(t *testing.T) {
+	server := newTestServer(t, func(request map[string]any) []any {
+		params, _ := request["params"].(map[string]any)
+		symbols, _ := params["symbol"].([]any)
+		var responses []any
+		for _, symbol := range symbols {
+			responses = append(responses, map[string]any{
+				"method":  request["method"],
+				"result":  map[string]any{"channel": params["channel"], "symbol": symbol},
+				"success": true,
+			})
+		}
+		return responses
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	acks := make(chan string, 10)
+	ws.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
+		acks <- e.Data.String()
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	if err := ws.SubTicker([]string{"BTC/USD", "ETH/USD"}); err != nil {
+		t.Fatalf("SubTicker: %s", err)
+	}
+	<-acks
+	<-acks
+	if !ws.IsSubscribed("ticker", "BTC/USD") || !ws.IsSubscribed("ticker", "ETH/USD") {
+		t.Errorf("expected BTC/USD and ETH/USD to be subscribed, got %v", ws.Subscriptions())
+	}
+	if err := ws.UnsubTicker([]string{"BTC/USD"}); err != nil {
+		t.Fatalf("UnsubTicker: %s", err)
+	}
+	<-acks
+	if subscriptions := ws.Subscriptions(); len(subscriptions["ticker"]) != 1 || subscriptions["ticker"][0] != "ETH/USD" {
+		t.Errorf("expected only ETH/USD to be subscribed, got %v", subscriptions)
+	}
+	if registry := ws.Registry.List(); len(registry) != 1 || registry[0].Symbol != "ETH/USD" {
+		t.Errorf("expected only ETH/USD in the registry, got %v", registry)
+	}
+}
+
+func TestWebSocketTokenRefresh(t *testing.T) {
+	var fetched atomic.Int32
+	rest := NewREST()
+	rest.PublicKey = "key"
+	rest.PrivateKey = "c2VjcmV0"
+	rest.Executor = func(r *http.Request) (*http.Response, error) {
+		token := fmt.Sprintf("token%d", fetched.Add(1))
+		body := fmt.Sprintf(`{"error":[],"result":{"token":%q,"expires":900}}`, token)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	server := newTestServer(t, func(request map[string]any) []any {
+		response := map[string]any{
+			"method":  request["method"],
+			"req_id":  request["req_id"],
+			"success": true,
+			"result":  map[string]any{"order_id": "OABC-123"},
+		}
+		if params, _ := request["params"].(map[string]any); params["token"] != "token2" {
+			response["success"] = false
+			response["error"] = "EAPI:Invalid token"
+		}
+		return []any{response}
+	})
+	defer server.Close()
+	tokens := NewTokenManager(rest)
+	clients := []*WebSocket{NewWebSocket(), NewWebSocket()}
+	for _, ws := range clients {
+		ws.Tokens = tokens
+		ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+		if err := ws.Connect(); err != nil {
+			t.Fatalf("Connect: %s", err)
+		}
+		defer func() {
+			_ = ws.Disconnect()
+		}()
+		if err := ws.Authenticate(); err != nil {
+			t.Fatalf("Authenticate: %s", err)
+		}
+	}
+	if n := fetched.Load(); n != 1 {
+		t.Errorf("expected the token to be shared, fetched %d", n)
+	}
+	pending, err := clients[0].AddOrder("market", "buy", 1, "BTC/USD")
+	if err != nil {
+		t.Fatalf("AddOrder: %s", err)
+	}
+	resp, err := pending.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %s", err)
+	}
+	if resp.Result.OrderID != "OABC-123" || clients[0].CurrentToken() != "token2" {
+		t.Errorf("expected the order to be placed with a new token, got %+v and %s", resp, clients[0].CurrentToken())
+	}
+	if err := clients[1].Authenticate(); err != nil {
+		t.Fatalf("Authenticate: %s", err)
+	}
+	if n := fetched.Load(); n != 2 || clients[1].CurrentToken() != "token2" {
+		t.Errorf("expected the refreshed token to be shared, fetched %d and got %s", n, clients[1].CurrentToken())
+	}
+}
+
+func TestWebSocketCancelAllOrdersAfter(t *testing.T) {
+	timeouts := make(chan any, 2)
+	server := newTestServer(t, func(request map[string]any) []any {
+		params, _ := request["params"].(map[string]any)
+		timeouts <- params["timeout"]
+		return []any{map[string]any{
+			"method":  request["method"],
+			"req_id":  request["req_id"],
+			"success": true,
+			"result":  map[string]any{"currentTime": "2024-01-01T00:00:00Z", "triggerTime": "2024-01-01T00:00:01Z"},
+		}}
+	})
+	defer server.Close()
+	ws := NewWebSocket()
+	ws.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer func() {
+		_ = ws.Disconnect()
+	}()
+	for _, timeout := range []time.Duration{500 * time.Millisecond, 0} {
+		pending, err := ws.CancelAllOrdersAfter(timeout)
+		if err != nil {
+			t.Fatalf("CancelAllOrdersAfter: %s", err)
+		}
+		if _, err := pending.Wait(); err != nil {
+			t.Fatalf("Wait: %s", err)
+		}
+	}
+	if first, second := <-timeouts, <-timeouts; first != float64(1) || second != float64(0) {
+		t.Errorf("expected timeouts of 1 and 0 seconds, got %v and %v", first, second)
+	}
+	d := NewDeadMansSwitch(ws)
+	d.Timeout = 500 * time.Millisecond
+	d.Interval = 100 * time.Millisecond
+	if err := d.Start(); err == nil {
+		_ = d.Stop()
+		t.Errorf("expected a timeout under 1 second to be rejected")
+	}
+}
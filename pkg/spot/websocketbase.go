@@ -1,7 +1,12 @@
 package spot
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/krakenfx/api-go/v2/internal/helper"
 	"github.com/krakenfx/api-go/v2/pkg/callback"
@@ -10,34 +15,95 @@ import (
 
 // WebSocketBase is the underlying of the [WebSocket] client.
 type WebSocketBase struct {
-	REST            *REST
+	REST *REST
+
+	// Last token retrieved by [WebSocketBase.Authenticate].
+	// Use [WebSocketBase.CurrentToken] to read it while the client is running, as it may be refreshed concurrently.
 	Token           string
 	OnAuthenticated *callback.Manager[string]
 	*kraken.WebSocket
+
+	// Source of the tokens used by [WebSocketBase.Authenticate], which may be shared between clients.
+	// It retrieves tokens with the initial REST client by default.
+	Tokens *TokenManager
+
+	// Time limit of [PendingRequest.Wait].
+	RequestTimeout time.Duration
+
+	// Whether the subscriptions are replayed after reconnecting.
+	Resubscribe bool
+	Registry    *kraken.SubscriptionRegistry
+	OnRestored  *callback.Manager[*kraken.RestoreEvent]
+
+	state      *kraken.SubscriptionState
+	tokenMux   sync.RWMutex
+	reqID      atomic.Int64
+	pending    map[int64]pendingResponse
+	pendingMux sync.Mutex
 }
 
 // NewWebSocketBase constructs a [WebSocketBase] struct with default values.
 func NewWebSocketBase() *WebSocketBase {
+	rest := NewREST()
 	b := &WebSocketBase{
-		REST:            NewREST(),
+		REST:            rest,
 		OnAuthenticated: callback.NewManager[string](),
 		WebSocket:       kraken.NewWebSocket(),
+		Tokens:          NewTokenManager(rest),
+		RequestTimeout:  10 * time.Second,
+		Resubscribe:     true,
+		Registry:        kraken.NewSubscriptionRegistry(),
+		OnRestored:      callback.NewManager[*kraken.RestoreEvent](),
+		state:           kraken.NewSubscriptionState(),
+		pending:         make(map[int64]pendingResponse),
 	}
 	b.URL = "wss://ws.kraken.com/v2"
+	b.PingInterval = 30 * time.Second
+	b.StaleTimeout = 60 * time.Second
+	b.Heartbeat = b.Ping
+	b.OnReceived.Recurring(func(e *callback.Event[*kraken.WebSocketMessage]) {
+		b.handleAck(e.Data)
+		b.handleResponse(e.Data)
+	})
+	// Clean up before the reconnect callback of [kraken.WebSocket] so that restored state is kept.
+	b.OnDisconnected.Priority(1, func(e *callback.Event[error]) {
+		b.state.Reset()
+		b.failPending(e.Data)
+	})
+	b.OnReconnected.Recurring(func(e *callback.Event[any]) {
+		if b.Resubscribe {
+			go b.Restore()
+		}
+	})
 	return b
 }
 
-// Authenticate retrieves a new WebSocket token from the REST API.
+// Authenticate retrieves a WebSocket token from [WebSocketBase.Tokens], which only calls the REST API if the cached token is missing or stale.
 func (b *WebSocketBase) Authenticate() error {
-	resp, err := b.REST.GetWebSocketsToken()
+	token, err := b.Tokens.Token()
 	if err != nil {
 		return fmt.Errorf("get websockets token: %w", err)
 	}
-	b.Token = resp.Result.Token
-	b.OnAuthenticated.Call(b.Token)
+	b.tokenMux.Lock()
+	b.Token = token
+	b.tokenMux.Unlock()
+	b.OnAuthenticated.Call(token)
 	return nil
 }
 
+// CurrentToken returns the last token retrieved by [WebSocketBase.Authenticate] or an empty string if the client is not authenticated.
+func (b *WebSocketBase) CurrentToken() string {
+	b.tokenMux.RLock()
+	defer b.tokenMux.RUnlock()
+	return b.Token
+}
+
+// reauthenticate discards a token rejected by the server and retrieves a new one.
+func (b *WebSocketBase) reauthenticate(token string) error {
+	b.Tokens.Invalidate(token)
+	return b.Authenticate()
+}
+
 // SendPublic submits a JSON-encoded map.
 func (b *WebSocketBase) SendPublic(m map[string]any) error {
 	return b.WriteJSON(m)
@@ -47,27 +113,189 @@ func (b *WebSocketBase) SendPublic(m map[string]any) error {
 func (b *WebSocketBase) SendPrivate(m map[string]any) error {
 	return b.WriteJSON(helper.Maps(map[string]any{
 		"params": map[string]any{
-			"token": b.Token,
+			"token": b.CurrentToken(),
 		},
 	}, m))
 }
 
-// SubPublic submits a subscription request.
+// Ping sends an application-level ping request, which the server answers with a pong.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/ping
+func (b *WebSocketBase) Ping() error {
+	return b.SendPublic(map[string]any{
+		"method": "ping",
+	})
+}
+
+// SubPublic submits a subscription request and stores it in the registry.
 func (b *WebSocketBase) SubPublic(channel string, options ...map[string]any) error {
-	return b.SendPublic(helper.Maps(map[string]any{
+	request := helper.Maps(map[string]any{
 		"method": "subscribe",
 		"params": map[string]any{
 			"channel": channel,
 		},
-	}, options...))
+	}, options...)
+	if err := b.SendPublic(request); err != nil {
+		return err
+	}
+	b.register(channel, false, request)
+	return nil
 }
 
-// SubPrivate submits a subscription request with the token included.
+// SubPrivate submits a subscription request with the token included and stores it in the registry.
 func (b *WebSocketBase) SubPrivate(channel string, options ...map[string]any) error {
-	return b.SendPrivate(helper.Maps(map[string]any{
+	request := helper.Maps(map[string]any{
 		"method": "subscribe",
 		"params": map[string]any{
 			"channel": channel,
 		},
-	}, options...))
+	}, options...)
+	if err := b.SendPrivate(request); err != nil {
+		return err
+	}
+	b.register(channel, true, request)
+	return nil
+}
+
+// UnsubPublic submits an unsubscribe request and removes the matching subscriptions from the registry.
+func (b *WebSocketBase) UnsubPublic(channel string, options ...map[string]any) error {
+	request := helper.Maps(map[string]any{
+		"method": "unsubscribe",
+		"params": map[string]any{
+			"channel": channel,
+		},
+	}, options...)
+	if err := b.SendPublic(request); err != nil {
+		return err
+	}
+	b.unregister(channel, request)
+	return nil
+}
+
+// UnsubPrivate submits an unsubscribe request with the token included and removes the matching subscriptions from the registry.
+func (b *WebSocketBase) UnsubPrivate(channel string, options ...map[string]any) error {
+	request := helper.Maps(map[string]any{
+		"method": "unsubscribe",
+		"params": map[string]any{
+			"channel": channel,
+		},
+	}, options...)
+	if err := b.SendPrivate(request); err != nil {
+		return err
+	}
+	b.unregister(channel, request)
+	return nil
+}
+
+// requestSymbols returns the symbols in the params of a request.
+func requestSymbols(request map[string]any) []string {
+	params, _ := request["params"].(map[string]any)
+	if params["symbol"] == nil {
+		return nil
+	}
+	symbols, _ := helper.StringSlice(params["symbol"])
+	return symbols
+}
+
+// register stores a subscription request in the registry with one entry per symbol.
+func (b *WebSocketBase) register(channel string, private bool, request map[string]any) {
+	symbols := requestSymbols(request)
+	if len(symbols) == 0 {
+		b.Registry.Add(&kraken.Subscription{Channel: channel, Private: private, Request: request})
+		return
+	}
+	for _, symbol := range symbols {
+		b.Registry.Add(&kraken.Subscription{
+			Channel: channel,
+			Symbol:  symbol,
+			Private: private,
+			Request: helper.Maps(request, map[string]any{
+				"params": map[string]any{
+					"symbol": []string{symbol},
+				},
+			}),
+		})
+	}
+}
+
+// unregister removes the subscriptions of a channel matching the symbols of an unsubscribe request.
+func (b *WebSocketBase) unregister(channel string, request map[string]any) {
+	symbols := requestSymbols(request)
+	b.Registry.RemoveFunc(func(s *kraken.Subscription) bool {
+		return s.Channel == channel && (len(symbols) == 0 || slices.Contains(symbols, s.Symbol))
+	})
+}
+
+// handleAck records the subscription state from the responses of subscribe and unsubscribe requests.
+func (b *WebSocketBase) handleAck(m *kraken.WebSocketMessage) {
+	data, err := m.Map()
+	if err != nil {
+		return
+	}
+	method, _ := data["method"].(string)
+	success, _ := data["success"].(bool)
+	if !success {
+		if message, _ := data["error"].(string); isInvalidToken(kraken.ParseSpotError(message)) {
+			b.Tokens.Invalidate(b.CurrentToken())
+		}
+		return
+	}
+	if method != "subscribe" && method != "unsubscribe" {
+		return
+	}
+	result, _ := data["result"].(map[string]any)
+	channel, _ := result["channel"].(string)
+	symbol, _ := result["symbol"].(string)
+	b.state.Set(channel, symbol, method == "subscribe")
+}
+
+// Subscriptions returns the symbols by channel whose subscriptions were confirmed by the server.
+// Channels without symbols, such as executions, contain an empty symbol.
+func (b *WebSocketBase) Subscriptions() map[string][]string {
+	return b.state.Map()
+}
+
+// IsSubscribed returns whether the server confirmed the subscription of a symbol on a channel.
+// Use an empty symbol for channels without symbols.
+func (b *WebSocketBase) IsSubscribed(channel string, symbol string) bool {
+	return b.state.IsSubscribed(channel, symbol)
+}
+
+// Restore retrieves a new token if the client was authenticated and replays the stored subscriptions.
+// Private subscriptions are skipped if authentication fails.
+//
+// It is called after reconnecting if Resubscribe is enabled.
+func (b *WebSocketBase) Restore() {
+	event := &kraken.RestoreEvent{}
+	var errs []error
+	subscriptions := b.Registry.List()
+	private := b.CurrentToken() != ""
+	for _, subscription := range subscriptions {
+		private = private || subscription.Private
+	}
+	if private {
+		if err := b.Authenticate(); err != nil {
+			errs = append(errs, err)
+		} else {
+			event.Authenticated = true
+		}
+	}
+	for _, subscription := range subscriptions {
+		var err error
+		switch {
+		case !subscription.Private:
+			err = b.SendPublic(subscription.Request)
+		case event.Authenticated:
+			err = b.SendPrivate(subscription.Request)
+		default:
+			err = fmt.Errorf("not authenticated")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resubscribe %s: %w", subscription.Channel, err))
+			continue
+		}
+		event.Subscriptions = append(event.Subscriptions, subscription)
+	}
+	event.Err = errors.Join(errs...)
+	b.OnRestored.Call(event)
 }
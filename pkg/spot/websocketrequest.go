@@ -0,0 +1,228 @@
+package spot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/krakenfx/api-go/v2/internal/helper"
+	"github.com/krakenfx/api-go/v2/pkg/kraken"
+)
+
+// ErrRequestTimeout is returned when the response to a WebSocket request does not arrive in time.
+var ErrRequestTimeout = errors.New("websocket request timed out")
+
+// WebSocketResponse is the acknowledgement of a WebSocket method request.
+//
+// https://docs.kraken.com/api/docs/websocket-v2/add_order
+type WebSocketResponse[T any] struct {
+	Method  string `json:"method,omitempty"`
+	ReqID   int64  `json:"req_id,omitempty"`
+	Result  T      `json:"result,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	TimeIn  string `json:"time_in,omitempty"`
+	TimeOut string `json:"time_out,omitempty"`
+
+	// Number of orders cancelled by batch_cancel, which has no result.
+	OrdersCancelled int `json:"orders_cancelled,omitempty"`
+}
+
+// pendingResponse is implemented by [PendingRequest] to be resolved by the read loop.
+type pendingResponse interface {
+	resolve(m *kraken.WebSocketMessage)
+	fail(err error)
+}
+
+// PendingRequest is a future of the response to a WebSocket request with a `req_id`.
+type PendingRequest[T any] struct {
+	ReqID    int64
+	Method   string
+	timeout  time.Duration
+	cancel   func()
+	done     chan struct{}
+	once     sync.Once
+	response *WebSocketResponse[T]
+	err      error
+
+	// Resends a private request once with a new token after the server rejected the previous one.
+	retry func()
+}
+
+func (p *PendingRequest[T]) resolve(m *kraken.WebSocketMessage) {
+	var response WebSocketResponse[T]
+	if err := m.JSON(&response); err != nil {
+		p.once.Do(func() {
+			p.err = err
+			close(p.done)
+		})
+		return
+	}
+	var err error
+	if !response.Success {
+		err = fmt.Errorf("%s req_id %d: %w", p.Method, p.ReqID, kraken.ParseSpotError(response.Error))
+		if p.retry != nil && isInvalidToken(err) {
+			retry := p.retry
+			p.retry = nil
+			go retry()
+			return
+		}
+	}
+	p.once.Do(func() {
+		p.response = &response
+		p.err = err
+		close(p.done)
+	})
+}
+
+func (p *PendingRequest[T]) fail(err error) {
+	p.once.Do(func() {
+		p.err = fmt.Errorf("%s req_id %d: %w", p.Method, p.ReqID, err)
+		close(p.done)
+	})
+}
+
+// Done returns a channel that is closed once the response arrives or the request fails.
+func (p *PendingRequest[T]) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait blocks until the response arrives or [WebSocketBase.RequestTimeout] elapses.
+// An unsuccessful response is returned together with an error wrapping a [kraken.Error].
+func (p *PendingRequest[T]) Wait() (*WebSocketResponse[T], error) {
+	if p.timeout <= 0 {
+		return p.WaitContext(context.Background())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	return p.WaitContext(ctx)
+}
+
+// WaitContext is like [PendingRequest.Wait] but waits until ctx is done instead of the timeout.
+func (p *PendingRequest[T]) WaitContext(ctx context.Context) (*WebSocketResponse[T], error) {
+	select {
+	case <-p.done:
+		return p.response, p.err
+	case <-ctx.Done():
+	}
+	p.cancel()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		p.fail(ErrRequestTimeout)
+	} else {
+		p.fail(ctx.Err())
+	}
+	<-p.done
+	return p.response, p.err
+}
+
+// SendRequest submits a method request and returns a [PendingRequest] that resolves with the response of the same `req_id`.
+// A `req_id` is assigned if the request does not include one.
+// A private request rejected for an invalid token is sent once more with a new token from [WebSocketBase.Tokens].
+func SendRequest[T any](b *WebSocketBase, private bool, m map[string]any) (*PendingRequest[T], error) {
+	m = helper.Maps(m)
+	reqID, err := b.requestID(m["req_id"])
+	if err != nil {
+		return nil, err
+	}
+	m["req_id"] = reqID
+	method, _ := m["method"].(string)
+	pending := &PendingRequest[T]{
+		ReqID:   reqID,
+		Method:  method,
+		timeout: b.RequestTimeout,
+		done:    make(chan struct{}),
+	}
+	pending.cancel = func() {
+		b.pendingMux.Lock()
+		defer b.pendingMux.Unlock()
+		if b.pending[reqID] == pendingResponse(pending) {
+			delete(b.pending, reqID)
+		}
+	}
+	if private {
+		token := b.CurrentToken()
+		pending.retry = func() {
+			if err := b.reauthenticate(token); err != nil {
+				pending.fail(err)
+				return
+			}
+			b.pendingMux.Lock()
+			b.pending[reqID] = pending
+			b.pendingMux.Unlock()
+			if err := b.SendPrivate(m); err != nil {
+				pending.cancel()
+				pending.fail(err)
+			}
+		}
+	}
+	b.pendingMux.Lock()
+	b.pending[reqID] = pending
+	b.pendingMux.Unlock()
+	if private {
+		err = b.SendPrivate(m)
+	} else {
+		err = b.SendPublic(m)
+	}
+	if err != nil {
+		pending.cancel()
+		return nil, err
+	}
+	return pending, nil
+}
+
+// requestID converts a `req_id` value to an integer or assigns the next one if v is nil.
+func (b *WebSocketBase) requestID(v any) (int64, error) {
+	switch v := v.(type) {
+	case nil:
+		return b.reqID.Add(1), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("invalid req_id %v of type %T", v, v)
+	}
+}
+
+// handleResponse resolves the [PendingRequest] matching the `req_id` of a method response.
+func (b *WebSocketBase) handleResponse(m *kraken.WebSocketMessage) {
+	data, err := m.Map()
+	if err != nil {
+		return
+	}
+	if _, ok := data["success"]; !ok {
+		return
+	}
+	reqID, err := b.requestID(data["req_id"])
+	if err != nil || data["req_id"] == nil {
+		return
+	}
+	b.pendingMux.Lock()
+	pending, ok := b.pending[reqID]
+	delete(b.pending, reqID)
+	b.pendingMux.Unlock()
+	if ok {
+		pending.resolve(m)
+	}
+}
+
+// failPending fails all pending requests after the connection is lost.
+func (b *WebSocketBase) failPending(err error) {
+	b.pendingMux.Lock()
+	pending := b.pending
+	b.pending = make(map[int64]pendingResponse)
+	b.pendingMux.Unlock()
+	for _, p := range pending {
+		p.fail(fmt.Errorf("disconnected: %w", err))
+	}
+}